@@ -6,11 +6,10 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
-	"runtime"
 	"strconv"
+	"strings"
 
 	"github.com/hako/afto/deb"
 	"github.com/hako/afto/release"
@@ -48,24 +47,6 @@ func ParseDir(indir string) (bool, error) {
 	return valid, nil
 }
 
-// CheckDpkg checks the host system has the dpkg command installed.
-func CheckDpkg() error {
-	_, err := exec.LookPath("dpkg")
-	if err != nil {
-		return errors.New("Unable to find required command 'dpkg'")
-	}
-	return nil
-}
-
-// CheckBzip2 checks the host system has the bzip2 command installed. (Sometimes this happens.)
-func CheckBzip2() error {
-	_, err := exec.LookPath("bzip2")
-	if err != nil {
-		return errors.New("Unable to find required command 'bzip2'")
-	}
-	return nil
-}
-
 // GetRepo checks if the string dir matches a valid repo in the current directory.
 // This can be from a name or a path.
 func GetRepo(dir string) (string, error) {
@@ -86,29 +67,30 @@ func GetRepo(dir string) (string, error) {
 
 // ParseDeb parses a deb file and returns a *deb.Control struct.
 func ParseDeb(debName string) (*deb.Control, error) {
-	// Run dpkg --field *.deb
-	fields, parseerr := exec.Command("dpkg", "-f", debName).Output()
-	if parseerr != nil {
-		return nil, parseerr
-	}
-	c := deb.NewControl()
-	controlFile, errs := c.ParseString(string(fields))
-	if errs != nil {
-		return nil, errs
+	archive, err := deb.Open(debName)
+	if err != nil {
+		return nil, err
 	}
+	return archive.Control(), nil
+}
 
-	return controlFile, nil
+// LoadPackages reads every entry of a repo's Packages file.
+// The uncompressed Packages file is preferred over its compressed variants.
+func LoadPackages(repo string) ([]*deb.Packages, error) {
+	for _, name := range append([]string{"Packages"}, PackagesFiles(Compressions)...) {
+		path := filepath.Join(repo, name)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		return deb.OpenPackages(path)
+	}
+	return nil, errors.New("no Packages file found in " + repo)
 }
 
 // BzipPackages compresses the 'Packages' file Packages.bz2.
-// Note: The stlib package "compress/bzip2" does not support compression.
+// Deprecated: BzipPackages works on the current directory, use CompressPackages or a Builder.
 func BzipPackages() error {
-	// Run bzip2 -kf Packages.
-	_, bzcmderr := exec.Command("bzip2", "-kf", "Packages").Output()
-	if bzcmderr != nil {
-		return bzcmderr
-	}
-	return nil
+	return CompressPackages(".", []string{"bz2"})
 }
 
 // CheckDeb checks if the user has deb files ready to go to the repo.
@@ -170,55 +152,154 @@ func CheckDebWithFile(fp string) (string, error) {
 	return path, nil
 }
 
-// SignRepo signs the repo's Packages and Packages.bz2 using devs' GPG key.
-func SignRepo(fp string) error {
+// SignRepo signs the repo's Release files with the secret key in keyFile, creating Release.gpg and InRelease
+// next to the root Release file and the ones of dists/.
+// passphrase decrypts the key when it is encrypted.
+func SignRepo(fp string, keyFile string, passphrase []byte) error {
 	repo, fperr := GetRepo(fp)
 	if fperr != nil {
 		return fperr
 	}
-	releaseFile := repo + "/Release"
-	outputFile := repo + "/Release.gpg"
-	_, err := ioutil.ReadFile(releaseFile)
+	if keyFile == "" {
+		return errors.New("no signing key given. set signing_key in " + ConfigFile + " or use --key")
+	}
+	key, keyerr := LoadSigningKey(keyFile, passphrase)
+	if keyerr != nil {
+		return keyerr
+	}
+	dirs, err := ReleaseDirs(repo)
 	if err != nil {
 		return err
 	}
-	_, gpgerr := exec.Command("gpg", "-abs", "-o", outputFile, releaseFile).Output()
-	if gpgerr != nil {
-		return gpgerr
+	for _, d := range dirs {
+		if err := SignRelease(filepath.Join(repo, d), key); err != nil {
+			return errors.New(filepath.ToSlash(filepath.Join(d, "Release")) + ": " + err.Error())
+		}
 	}
 	return nil
 }
 
+// ReleaseDirs returns the directories of the repo dir holding a Release file, relative to dir and sorted.
+// The repo root is "", followed by the suites of the dists/ directory. (dists/stable)
+func ReleaseDirs(dir string) ([]string, error) {
+	var dirs []string
+	if _, err := os.Stat(filepath.Join(dir, "Release")); err == nil {
+		dirs = append(dirs, "")
+	}
+	suites, err := ioutil.ReadDir(filepath.Join(dir, "dists"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, s := range suites {
+		rel := filepath.Join("dists", s.Name())
+		if _, err := os.Stat(filepath.Join(dir, rel, "Release")); s.IsDir() && err == nil {
+			dirs = append(dirs, rel)
+		}
+	}
+	return dirs, nil
+}
+
+// IsSigned returns whether a Release file of the repo dir is signed, by Release.gpg or InRelease.
+// Signatures are not verified. (afto verify checks them)
+func IsSigned(dir string) (bool, error) {
+	dirs, err := ReleaseDirs(dir)
+	if err != nil {
+		return false, err
+	}
+	for _, d := range dirs {
+		if signedDir(filepath.Join(dir, d)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// signedDir returns whether the directory dir holds a signature of its Release file.
+func signedDir(dir string) bool {
+	for _, name := range []string{"Release.gpg", "InRelease"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
 // IsDeb returns whether the string is a deb file with regex.
 func IsDeb(filename string) bool {
 	re := regexp.MustCompile(`(\.deb)`)
 	return re.MatchString(filename)
 }
 
-// ReleaseFile generates a release file based on origin, label, desc codename and suite.
+// ReleaseFile generates a release file based on the origin, label, description, codename,
+// suite, architectures and components of the config.
+// The 'Packages' file in dir and its compressed variants in the config are signed in the release file.
 // It is recommended to generate this file for hosting a repo.
-func ReleaseFile(origin string, label string, desc string, codename string, suite string) (string, error) {
+func ReleaseFile(dir string, cfg *Config) (string, error) {
+	return ReleaseFileFor(dir, cfg, append([]string{"Packages"}, PackagesFiles(cfg.Compressions)...))
+}
+
+// ReleaseFileFor is like ReleaseFile but signs the index files in dir named by indexes.
+// (main/binary-iphoneos-arm/Packages)
+func ReleaseFileFor(dir string, cfg *Config, indexes []string) (string, error) {
 	r := release.NewRelease()
-	r.SetOrigin(origin)
-	r.SetLabel(label)
-	r.SetDescription(desc)
-	r.SetCodename(codename)
-	r.SetSuite(suite)
-
-	// Get Packages and Packages.bz2
-	packages, err := ioutil.ReadFile("Packages")
-	if err != nil {
-		return "", err
-	}
-	packagesbz, err := ioutil.ReadFile("Packages.bz2")
-	if err != nil {
-		return "", err
+	r.SetOrigin(cfg.Origin)
+	r.SetLabel(cfg.Label)
+	r.SetDescription(cfg.Description)
+	r.SetCodename(cfg.Codename)
+	r.SetSuite(cfg.Suite)
+	r.SetArch(strings.Join(cfg.Architectures, " "))
+	r.SetComponents(strings.Join(cfg.Components, " "))
+	r.SetNotAutomatic(cfg.NotAutomatic)
+	r.SetAcquireByHash(cfg.AcquireByHash)
+	if cfg.ValidFor > 0 {
+		r.SetValidUntil(r.Date().Add(cfg.ValidFor))
 	}
 
-	r.AddPackageSignature(packages, packagesbz)
+	// Get Packages and every compressed Packages file.
+	for _, name := range indexes {
+		data, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return "", err
+		}
+		r.AddIndexFile(name, data)
+	}
 	return r.Generate(), nil
 }
 
+// ByHash copies the index files in dir to by-hash/<hash field>/<hash> in dir,
+// so clients of a repo with Acquire-By-Hash can fetch them by hash. (by-hash/SHA256/<hash>)
+func ByHash(dir string, files []string) error {
+	r := release.NewRelease()
+	for _, name := range files {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		r.AddIndexFile(name, data)
+	}
+	for _, f := range r.Files() {
+		for _, field := range release.HashFields {
+			hashDir := filepath.Join(dir, "by-hash", field)
+			if err := os.MkdirAll(hashDir, 0755); err != nil {
+				return err
+			}
+			if err := Copy(filepath.Join(dir, f.Name), filepath.Join(hashDir, f.Hash(field))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// PackagesFiles returns the file names of the compressed Packages files in comps. (Packages.bz2)
+func PackagesFiles(comps []string) []string {
+	var names []string
+	for _, c := range comps {
+		names = append(names, "Packages."+c)
+	}
+	return names
+}
+
 // Copy copies a file from source to destination.
 // Note: Copy is not in the stdlib so kudos to @elazarl
 func Copy(source string, destination string) error {
@@ -239,18 +320,3 @@ func Copy(source string, destination string) error {
 	}
 	return dst.Close()
 }
-
-// DetectPlatform returns what host system the user is running.
-func DetectPlatform() (string, error) {
-	hostOS := runtime.GOOS
-	switch hostOS {
-	case "darwin":
-		return "Since you're on a Mac, you can install dpkg via brew. \nIf you have brew installed type `brew install dpkg` and try again.", nil
-	case "linux":
-		return "Since you're on Linux, you can install dpkg by using `sudo apt-get install dpkg`.", nil
-	case "windows":
-		return "It looks like dpkg is not available on Windows. \nYou can stil use afto, you cannot build your repo without dpkg.", nil
-	default:
-		return "", errors.New("unable to detect your os")
-	}
-}
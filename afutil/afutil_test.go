@@ -1,10 +1,28 @@
 package afutil
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/hako/afto/deb"
+	"github.com/hako/afto/release"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
 )
 
+// testData is the absolute path to the test_data directory, as tests change directory.
+var testData, _ = filepath.Abs("../test_data")
+
 func setup() {
 	os.Mkdir("tests", 0644)
 }
@@ -32,14 +50,6 @@ func TestNonExistence(t *testing.T) {
 	tearDown()
 }
 
-// Testing if the host user has the dpkg command.
-func TestDpkgCommandExists(t *testing.T) {
-	err := CheckDpkg()
-	if err != nil {
-		t.Errorf("CheckDpkg() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", nil, err)
-	}
-}
-
 // Testing .deb file regex
 func TestIsDeb(t *testing.T) {
 	var paramTests = []struct {
@@ -65,15 +75,7 @@ func TestIsDeb(t *testing.T) {
 	}
 }
 
-// Testing if the host user has the bzip2 command.
-func TestBzip2Exists(t *testing.T) {
-	err := CheckBzip2()
-	if err != nil {
-		t.Errorf("CheckBzip2() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", nil, err)
-	}
-}
-
-// Testing bzip2 packages using exec.
+// Testing bzip2 packages natively.
 func TestBzipPackages(t *testing.T) {
 	os.Chdir("../test_data/packages/")
 	err := BzipPackages()
@@ -114,7 +116,1009 @@ func TestCheckDebWithPath(t *testing.T) {
 	os.Chdir("../")
 }
 
+// Testing Packages generation against the output of dpkg-scanpackages.
+func TestScanPackages(t *testing.T) {
+	want := `Package: com.yourcompany.tweakexample
+Version: 0.0.1-2
+Architecture: iphoneos-arm
+Maintainer: foo
+Installed-Size: 88
+Depends: mobilesubstrate
+Filename: ./com.yourcompany.tweakexample_0.0.1-2_iphoneos-arm.deb
+Size: 2166
+MD5sum: b360935902c4617dfe14d489e8e52e20
+SHA1: 58fbdda814019ef5298f526633628c4703759452
+SHA256: 0c781e30e138b8b0e1e7e0a54ebf599c5e95819ad61e29991e8f308b7eb9e90e
+Section: Tweaks
+Description: An awesome MobileSubstrate tweak!
+Author: foo
+Name: TweakExample
+
+`
+	got, err := ScanPackages(filepath.Join(testData, "deb"), []string{"com.yourcompany.tweakexample_0.0.1-2_iphoneos-arm.deb"})
+	if err != nil {
+		t.Fatalf("ScanPackages() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", nil, err)
+	}
+	if string(got) != want {
+		t.Errorf("ScanPackages() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", want, got)
+	}
+}
+
+// Testing continuation lines and field names are written like dpkg-scanpackages.
+func TestSortScanFields(t *testing.T) {
+	para := sortScanFields([]deb.Field{
+		{Name: "sileodepiction", Value: "bar"},
+		{Name: "Description", Value: "short\nlong\n\n..\ntrailing"},
+		{Name: "package", Value: "foo"},
+	})
+	want := "Package: foo\nDescription: short\n long\n .\n ..\n trailing\nSileodepiction: bar\n"
+	if para.String() != want {
+		t.Errorf("sortScanFields() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", want, para.String())
+	}
+}
+
+// Testing every Packages compression decompresses back to the input.
+func TestCompress(t *testing.T) {
+	packages, err := ioutil.ReadFile(filepath.Join(testData, "packages", "Packages"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range Compressions {
+		data, err := Compress(packages, c)
+		if err != nil {
+			t.Errorf("Compress(%q) failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", c, nil, err)
+			continue
+		}
+		var r io.Reader
+		switch c {
+		case "bz2":
+			r = bzip2.NewReader(bytes.NewReader(data))
+		case "gz":
+			r, err = gzip.NewReader(bytes.NewReader(data))
+		case "xz":
+			r, err = xz.NewReader(bytes.NewReader(data))
+		case "zst":
+			r, err = zstd.NewReader(bytes.NewReader(data))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ioutil.ReadAll(r)
+		if err != nil || !bytes.Equal(got, packages) {
+			t.Errorf("Compress(%q) failed test. decompressed output does not match Packages.", c)
+		}
+	}
+}
+
+// Testing parsing of the compressions option.
+func TestParseCompressions(t *testing.T) {
+	var paramTests = []struct {
+		params string
+		valid  bool
+	}{
+		{"bz2", true},
+		{"bz2,xz", true},
+		{".gz, .zst", true},
+		{"", false},
+		{"bz2,lz4", false},
+	}
+
+	for _, comp := range paramTests {
+		_, err := ParseCompressions(comp.params)
+		if (err == nil) != comp.valid {
+			t.Errorf("ParseCompressions(%q) failed test. \n\n\rWant: \n\r\"%t\" \n\rGot: \n\r\"%v\" \n\n", comp.params, comp.valid, err)
+		}
+	}
+}
+
+// Testing loading the entries of a repo's Packages file.
+func TestLoadPackages(t *testing.T) {
+	entries, err := LoadPackages(filepath.Join(testData, "packages"))
+	if err != nil {
+		t.Fatalf("LoadPackages() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", nil, err)
+	}
+	if len(entries) != 1 || entries[0].Package() != "com.yourcompany.tweakexample" {
+		t.Errorf("LoadPackages() failed test. entries were not loaded.")
+	}
+	if _, err := LoadPackages(filepath.Join(testData, "deb")); err == nil {
+		t.Errorf("LoadPackages() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", "error", err)
+	}
+}
+
 func tearDown() {
 	os.Remove("tests")
 	os.Remove("Packages.bz2")
 }
+
+// Testing unsatisfiable dependencies between the packages of a repo.
+func TestCheckDependencies(t *testing.T) {
+	var controls []*deb.Packages
+	for _, control := range []string{
+		"Package: com.example.lib\nVersion: 1.0\nProvides: libexample (= 1.0), libcompat\n",
+		"Package: com.example.tweak\nVersion: 2.0\nDepends: mobilesubstrate, com.example.lib (>= 1.0)\n",
+		"Package: com.example.old\nVersion: 0.1\nDepends: com.example.lib (<< 1.0)\n",
+		"Package: com.example.virtual\nVersion: 0.2\nPre-Depends: libexample (>= 0.9), libcompat\n",
+		"Package: com.example.broken\nVersion: 0.3\nDepends: libcompat (>= 1.0) | com.example.tweak (>> 2.0), firmware | com.example.old\n",
+	} {
+		c, err := deb.NewPackages().ParseString(control)
+		if err != nil {
+			t.Fatal(err)
+		}
+		controls = append(controls, c)
+	}
+
+	problems, err := CheckDependencies(controls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"com.example.broken 0.3: Depends \"libcompat (>= 1.0) | com.example.tweak (>> 2.0)\" cannot be satisfied",
+		"com.example.old 0.1: Depends \"com.example.lib (<< 1.0)\" cannot be satisfied",
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("CheckDependencies() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", want, problems)
+	}
+	for i, p := range problems {
+		if p.String() != want[i] {
+			t.Errorf("CheckDependencies() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", want[i], p.String())
+		}
+	}
+
+	// Removing com.example.lib breaks the packages depending on it, but not the ones already broken.
+	problems, err = CheckRemoval(controls[1:], controls[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{
+		"com.example.tweak 2.0: Depends \"com.example.lib (>= 1.0)\" cannot be satisfied",
+		"com.example.virtual 0.2: Pre-Depends \"libexample (>= 0.9)\" cannot be satisfied",
+		"com.example.virtual 0.2: Pre-Depends \"libcompat\" cannot be satisfied",
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("CheckRemoval() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", want, problems)
+	}
+	for i, p := range problems {
+		if p.String() != want[i] {
+			t.Errorf("CheckRemoval() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", want[i], p.String())
+		}
+	}
+
+	// Dependencies are only satisfied by packages of the same architecture, or of all.
+	var archControls []*deb.Packages
+	for _, control := range []string{
+		"Package: com.example.arm64lib\nVersion: 1.0\nArchitecture: iphoneos-arm64\n",
+		"Package: com.example.common\nVersion: 1.0\nArchitecture: all\n",
+		"Package: com.example.armtweak\nVersion: 1.0\nArchitecture: iphoneos-arm\nDepends: com.example.arm64lib, com.example.common, com.example.arm64lib [!iphoneos-arm], com.example.arm64lib:any\n",
+		"Package: com.example.arm64tweak\nVersion: 1.0\nArchitecture: iphoneos-arm64\nDepends: com.example.arm64lib, com.example.common, com.example.arm64lib [iphoneos-arm64]\n",
+	} {
+		c, err := deb.NewPackages().ParseString(control)
+		if err != nil {
+			t.Fatal(err)
+		}
+		archControls = append(archControls, c)
+	}
+	var archTests = []struct {
+		name string
+		run  func() ([]DependencyProblem, error)
+		want []string
+	}{
+		{"CheckDependencies", func() ([]DependencyProblem, error) { return CheckDependencies(archControls) }, []string{
+			"com.example.armtweak 1.0: Depends \"com.example.arm64lib\" cannot be satisfied",
+		}},
+		{"CheckRemoval", func() ([]DependencyProblem, error) { return CheckRemoval(archControls[1:], archControls[:1]) }, []string{
+			"com.example.arm64tweak 1.0: Depends \"com.example.arm64lib\" cannot be satisfied",
+			"com.example.arm64tweak 1.0: Depends \"com.example.arm64lib [iphoneos-arm64]\" cannot be satisfied",
+			"com.example.armtweak 1.0: Depends \"com.example.arm64lib:any\" cannot be satisfied",
+		}},
+	}
+	for _, a := range archTests {
+		problems, err := a.run()
+		var got []string
+		for _, p := range problems {
+			got = append(got, p.String())
+		}
+		if err != nil || strings.Join(got, "\n") != strings.Join(a.want, "\n") {
+			t.Errorf("%s() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" (%v) \n\n", a.name, a.want, got, err)
+		}
+	}
+}
+
+// Testing parsing a config file and its defaults.
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig("afto.yaml", []byte("origin: Example Repo\nsuite: stable\narchitectures: [iphoneos-arm, iphoneos-arm64]\nlayout: pool\ncompressions: bz2, xz\nport: 8080\nvalid_for: 7d\nacquire_by_hash: true\n"))
+	if err != nil {
+		t.Fatalf("ParseConfig() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", nil, err)
+	}
+	var paramTests = []struct {
+		got  string
+		want string
+	}{
+		{cfg.Origin, "Example Repo"},
+		{cfg.Suite, "stable"},
+		{cfg.Codename, "afto"},
+		{strings.Join(cfg.Architectures, " "), "iphoneos-arm iphoneos-arm64"},
+		{strings.Join(cfg.Components, " "), "main"},
+		{strings.Join(cfg.Compressions, " "), "bz2 xz"},
+		{cfg.Port, "8080"},
+	}
+	suites, err := ParseConfig("afto.yaml", []byte("suites: stable, beta\nlayout: pool\n"))
+	if err != nil || suites.Suite != "stable" || suites.ForSuite("beta").Suite != "beta" || suites.ForSuite("beta").Suites != nil {
+		t.Errorf("ParseConfig() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" (%v) \n\n", "suite stable", suites, err)
+	}
+	if cfg.ValidFor != 7*24*time.Hour || !cfg.AcquireByHash {
+		t.Errorf("ParseConfig() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", "valid_for: 7d", cfg.ValidFor)
+	}
+	for _, p := range paramTests {
+		if p.got != p.want {
+			t.Errorf("ParseConfig() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", p.want, p.got)
+		}
+	}
+}
+
+// Testing config validation errors name the offending key.
+func TestParseConfigErrors(t *testing.T) {
+	var paramTests = []struct {
+		params string
+		key    string
+	}{
+		{"sutie: stable\n", "sutie"},
+		{"port: [2468]\n", "port"},
+		{"port: 99999\n", "port"},
+		{"codename: my repo\n", "codename"},
+		{"components: []\n", "components"},
+		{"compressions: rar\n", "compressions"},
+		{"description: |\n  two\n  lines\n", "description"},
+		{"icon: missing.png\n", "icon"},
+		{"origin: a\norigin: b\n", "origin"},
+		{"valid_for: soon\n", "valid_for"},
+		{"acquire_by_hash: maybe\n", "acquire_by_hash"},
+		{"layout: debian\n", "layout"},
+		{"components: [main, beta]\n", "components"},
+		{"architectures: [iphoneos-arm, iphoneos-arm64]\nlayout: flat\n", "architectures"},
+		{"keep_versions: 0\n", "keep_versions"},
+		{"keep_versions: all\n", "keep_versions"},
+		{"keep_for: forever\n", "keep_for"},
+		{"suites: [beta, beta]\nlayout: pool\n", "suites"},
+		{"suites: [beta, stable]\nsuite: testing\nlayout: pool\n", "suite"},
+		{"suites: [beta, stable]\n", "suites"},
+		{"1: x\n", "1"},
+		{"true: y\n", "true"},
+	}
+
+	for _, p := range paramTests {
+		_, err := ParseConfig("afto.yaml", []byte(p.params))
+		cerr, ok := err.(*ConfigError)
+		if !ok || cerr.Key != p.key {
+			t.Errorf("ParseConfig(%q) failed test. \n\n\rWant: \n\r\"key %s\" \n\rGot: \n\r\"%v\" \n\n", p.params, p.key, err)
+		}
+	}
+
+	// Errors point to the line of the offending key, validation errors too.
+	var lineTests = []struct {
+		params string
+		want   string
+	}{
+		{"origin: a\nsutie: stable\n", "afto.yaml:2: key \"sutie\": unknown key"},
+		{"origin: a\n\nport: [2468]\n", "afto.yaml:3: key \"port\": must be a string"},
+		{"origin: a\nlayout: flat\narchitectures: [iphoneos-arm, iphoneos-arm64]\n", "afto.yaml:3: key \"architectures\""},
+	}
+	for _, p := range lineTests {
+		_, err := ParseConfig("afto.yaml", []byte(p.params))
+		if err == nil || !strings.HasPrefix(err.Error(), p.want) {
+			t.Errorf("ParseConfig(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", p.params, p.want, err)
+		}
+	}
+}
+
+// Testing signing a repo with an encrypted key and verifying its signatures.
+func TestSignRepo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "afto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "Packages"), []byte("Package: foo\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "Release"), []byte("Origin: afto beta repo\nMD5Sum:\n 82c88dbffc96d5a3d0e62207e8cdb288 13 Packages\n"), 0644)
+	secret := filepath.Join(testData, "keys", "secret.asc")
+
+	for _, passphrase := range [][]byte{nil, []byte("wrong")} {
+		if err := SignRepo(dir, secret, passphrase); err == nil {
+			t.Errorf("SignRepo(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", passphrase, "error", err)
+		}
+	}
+	if err := SignRepo(dir, secret, []byte("afto")); err != nil {
+		t.Fatalf("SignRepo() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", nil, err)
+	}
+
+	keyring, err := ReadKeyRing(filepath.Join(testData, "keys", "public.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyRelease(dir, keyring); err != nil {
+		t.Errorf("VerifyRelease() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", nil, err)
+	}
+	// A changed Release file no longer matches its signatures.
+	ioutil.WriteFile(filepath.Join(dir, "Release"), []byte("Origin: evil repo\n"), 0644)
+	if err := VerifyRelease(dir, keyring); err == nil {
+		t.Errorf("VerifyRelease() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", "error", err)
+	}
+}
+
+// Testing reading the passphrase from the environment before the passphrase file.
+func TestReadPassphrase(t *testing.T) {
+	file := filepath.Join(os.TempDir(), "afto-passphrase")
+	ioutil.WriteFile(file, []byte("from file\nignored\n"), 0600)
+	defer os.Remove(file)
+
+	os.Unsetenv(PassphraseEnv)
+	if p, err := ReadPassphrase(file); err != nil || string(p) != "from file" {
+		t.Errorf("ReadPassphrase(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", file, "from file", p)
+	}
+	if p, err := ReadPassphrase(""); err != nil || p != nil {
+		t.Errorf("ReadPassphrase(%q) failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%s\" \n\n", "", nil, p)
+	}
+	os.Setenv(PassphraseEnv, "from env")
+	defer os.Unsetenv(PassphraseEnv)
+	if p, err := ReadPassphrase(file); err != nil || string(p) != "from env" {
+		t.Errorf("ReadPassphrase(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", file, "from env", p)
+	}
+}
+
+// Testing verifying a signed repo, then breaking it.
+func TestVerifyRepo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "afto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	debName := "com.yourcompany.tweakexample_0.0.1-2_iphoneos-arm.deb"
+	if err := Copy(filepath.Join(testData, "deb", debName), filepath.Join(dir, debName)); err != nil {
+		t.Fatal(err)
+	}
+	packages, err := ScanPackages(dir, []string{debName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(filepath.Join(dir, "Packages"), packages, 0644)
+	r := release.NewRelease()
+	r.AddIndexFile("Packages", packages)
+	ioutil.WriteFile(filepath.Join(dir, "Release"), []byte(r.Generate()), 0644)
+	dists := filepath.Join(dir, "dists", "stable")
+	os.MkdirAll(filepath.Join(dists, "main", "binary-iphoneos-arm"), 0755)
+	ioutil.WriteFile(filepath.Join(dists, "main", "binary-iphoneos-arm", "Packages"), packages, 0644)
+	r = release.NewRelease()
+	r.AddIndexFile("main/binary-iphoneos-arm/Packages", packages)
+	ioutil.WriteFile(filepath.Join(dists, "Release"), []byte(r.Generate()), 0644)
+	if err := SignRepo(dir, filepath.Join(testData, "keys", "secret.asc"), []byte("afto")); err != nil {
+		t.Fatal(err)
+	}
+	keyring, err := ReadKeyRing(filepath.Join(testData, "keys", "public.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := VerifyRepo(dir, keyring)
+	if err != nil || !report.OK || !report.Signed || report.Packages != 1 || report.Indexes != 2 {
+		t.Fatalf("VerifyRepo() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%+v\" (%v) \n\n", "ok", report, err)
+	}
+
+	// The Release files of dists/ are signed and verified too.
+	data, _ := ioutil.ReadFile(filepath.Join(dists, "Release"))
+	ioutil.WriteFile(filepath.Join(dists, "Release"), append(data, "Label: evil repo\n"...), 0644)
+	report, err = VerifyRepo(dir, keyring)
+	if err != nil || report.OK || report.Signed || len(report.Problems) != 2 || report.Problems[0].File != "dists/stable/InRelease" {
+		t.Errorf("VerifyRepo() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" (%v) \n\n", "bad dists/stable signatures", report.Problems, err)
+	}
+	os.RemoveAll(filepath.Join(dir, "dists"))
+
+	// An orphaned deb, a dangling Filename and an unlisted index. Debs left in staging directories are ignored.
+	os.Rename(filepath.Join(dir, debName), filepath.Join(dir, "orphan.deb"))
+	os.Mkdir(filepath.Join(dir, StagingPrefix+"leftover"), 0755)
+	Copy(filepath.Join(dir, "orphan.deb"), filepath.Join(dir, StagingPrefix+"leftover", "orphan.deb"))
+	Copy(filepath.Join(dir, "Packages"), filepath.Join(dir, "Packages.gz"))
+	report, err = VerifyRepo(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"dangling: ./" + debName + ": listed for com.yourcompany.tweakexample 0.0.1-2 but does not exist",
+		"orphan: ./orphan.deb: not listed in Packages",
+		"index: Packages.gz: not listed in Release",
+	}
+	if report.OK || len(report.Problems) != len(want) {
+		t.Fatalf("VerifyRepo() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", want, report.Problems)
+	}
+	for i, p := range report.Problems {
+		if p.String() != want[i] {
+			t.Errorf("VerifyRepo() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", want[i], p.String())
+		}
+	}
+}
+
+// Testing only new and changed debs are read when scanning with a cache.
+func TestScanPackagesCached(t *testing.T) {
+	dir, err := ioutil.TempDir("", "afto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	debs := []string{"a.deb", "b.deb"}
+	for _, d := range debs {
+		Copy(filepath.Join(testData, "deb", "com.yourcompany.tweakexample_0.0.1-2_iphoneos-arm.deb"), filepath.Join(dir, d))
+	}
+	want, err := ScanPackages(dir, debs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cachePath := filepath.Join(dir, CacheFile)
+	var paramTests = []struct {
+		name   string
+		change func()
+		hits   int
+		misses int
+	}{
+		{"empty cache", func() {}, 0, 2},
+		{"unchanged", func() {}, 2, 0},
+		{"touched", func() { os.Chtimes(filepath.Join(dir, "a.deb"), time.Now(), time.Now().Add(time.Hour)) }, 2, 0},
+		{"replaced", func() {
+			Copy(filepath.Join(testData, "archive", "com.example.compressed_2.0~beta1-3_iphoneos-arm64.xz.deb"), filepath.Join(dir, "b.deb"))
+		}, 1, 1},
+	}
+
+	for _, p := range paramTests {
+		p.change()
+		cache := LoadCache(cachePath)
+		got, err := ScanPackagesCached(dir, debs, cache)
+		if err != nil {
+			t.Fatalf("ScanPackagesCached(%q) failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", p.name, nil, err)
+		}
+		if cache.Hits != p.hits || cache.Misses != p.misses {
+			t.Errorf("ScanPackagesCached(%q) failed test. \n\n\rWant: \n\r\"%d hits, %d misses\" \n\rGot: \n\r\"%d hits, %d misses\" \n\n", p.name, p.hits, p.misses, cache.Hits, cache.Misses)
+		}
+		if p.name != "replaced" && !bytes.Equal(got, want) {
+			t.Errorf("ScanPackagesCached(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", p.name, want, got)
+		}
+		if err := cache.Save(cachePath); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Testing staged files are published into the repo, and the repo is restored when publishing fails.
+func TestStagingPublish(t *testing.T) {
+	dir, err := ioutil.TempDir("", "afto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "Packages"), []byte("old"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "Packages.gz"), []byte("old"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "new.deb"), []byte("deb"), 0644)
+
+	// A directory in place of the Release file makes publishing fail after Packages is replaced.
+	os.MkdirAll(filepath.Join(dir, "Release", "blocked"), 0755)
+	s, err := NewStaging(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.WriteFile("Packages", []byte("new"))
+	s.WriteFile("Release", []byte("new"))
+	s.Adopt(filepath.Join(dir, "new.deb"), "pool/new.deb")
+	s.Remove("Packages.gz")
+	if err := s.Publish(); err == nil {
+		t.Errorf("Publish() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", "error", err)
+	}
+	s.Abort()
+	for name, want := range map[string]string{"Packages": "old", "Packages.gz": "old", "new.deb": "deb"} {
+		if got, _ := ioutil.ReadFile(filepath.Join(dir, name)); string(got) != want {
+			t.Errorf("Publish(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", name, want, got)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pool", "new.deb")); err == nil {
+		t.Errorf("Publish(%q) failed test. the adopted file was published.", "pool/new.deb")
+	}
+
+	os.RemoveAll(filepath.Join(dir, "Release"))
+	s, err = NewStaging(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.WriteFile("Packages", []byte("new"))
+	s.WriteFile("Release", []byte("new"))
+	s.Adopt(filepath.Join(dir, "new.deb"), "pool/new.deb")
+	s.Remove("Packages.gz")
+	if err := s.Publish(); err != nil {
+		t.Fatalf("Publish() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", nil, err)
+	}
+	for name, want := range map[string]string{"Packages": "new", "Release": "new", "pool/new.deb": "deb"} {
+		if got, _ := ioutil.ReadFile(filepath.Join(dir, name)); string(got) != want {
+			t.Errorf("Publish(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", name, want, got)
+		}
+	}
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 3 {
+		t.Errorf("Publish() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%d files\" \n\n", "Packages, Release and pool", len(files))
+	}
+}
+
+// Testing a staging directory left by a crash halfway through publishing is rolled back.
+func TestRecoverStaging(t *testing.T) {
+	dir, err := ioutil.TempDir("", "afto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "Packages"), []byte("old"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "Packages.gz"), []byte("old"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "new.deb"), []byte("deb"), 0644)
+
+	// Crash after the deb and Packages are published and Packages.gz is removed.
+	s, err := NewStaging(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.WriteFile("Packages", []byte("new"))
+	s.Adopt(filepath.Join(dir, "new.deb"), "pool/new.deb")
+	s.backup("Packages")
+	s.backup("Packages.gz")
+	s.record(journalRecord{State: stagingPublishing, Names: []string{"pool/new.deb", "Packages"}, Removed: []string{"Packages.gz"},
+		Backups: map[string]bool{"Packages": true, "Packages.gz": true}})
+	os.MkdirAll(filepath.Join(dir, "pool"), 0755)
+	os.Rename(s.Path("pool/new.deb"), filepath.Join(dir, "pool", "new.deb"))
+	os.Rename(s.Path("Packages"), filepath.Join(dir, "Packages"))
+	os.Remove(filepath.Join(dir, "Packages.gz"))
+	s.closeJournal()
+
+	// The staging directory of a running process is left alone.
+	if err := RecoverStaging(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.Dir()); err != nil {
+		t.Errorf("RecoverStaging(%q) failed test. the staging directory of a running process was removed.", dir)
+	}
+
+	// Once its process is gone, the repo is rolled back and the adopted deb moved back.
+	journal := filepath.Join(s.Dir(), journalFile)
+	data, _ := ioutil.ReadFile(journal)
+	dead := strings.Replace(string(data), "\"pid\":"+strconv.Itoa(os.Getpid()), "\"pid\":2147483646", 1)
+	ioutil.WriteFile(journal, []byte(dead), 0644)
+	if err := RecoverStaging(dir); err != nil {
+		t.Fatalf("RecoverStaging(%q) failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", dir, nil, err)
+	}
+	for name, want := range map[string]string{"Packages": "old", "Packages.gz": "old", "new.deb": "deb"} {
+		if got, _ := ioutil.ReadFile(filepath.Join(dir, name)); string(got) != want {
+			t.Errorf("RecoverStaging(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", name, want, got)
+		}
+	}
+	for _, path := range []string{filepath.Join(dir, "pool", "new.deb"), s.Dir()} {
+		if _, err := os.Stat(path); err == nil {
+			t.Errorf("RecoverStaging(%q) failed test. %s is left behind.", dir, path)
+		}
+	}
+}
+
+// Testing the packages added, removed and changed between two Packages files.
+func TestDiffPackages(t *testing.T) {
+	parse := func(entries ...string) []*deb.Packages {
+		var ps []*deb.Packages
+		for _, e := range entries {
+			p, err := deb.NewPackages().ParseString(e)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ps = append(ps, p)
+		}
+		return ps
+	}
+	old := parse(
+		"Package: com.example.a\nVersion: 1.0\nSHA256: aa\n",
+		"Package: com.example.b\nVersion: 1.0\nSHA256: bb\n",
+		"Package: com.example.c\nVersion: 1.0\nSHA256: cc\n",
+		"Package: com.example.d\nVersion: 1.0\nSHA256: dd\n",
+	)
+	new := parse(
+		"Package: com.example.a\nVersion: 1.0\nSHA256: aa\n",
+		"Package: com.example.c\nVersion: 1.1\nSHA256: c1\n",
+		"Package: com.example.d\nVersion: 1.0\nSHA256: d1\n",
+		"Package: com.example.e\nVersion: 2.0\nSHA256: ee\n",
+	)
+	want := []string{
+		"removed: com.example.b 1.0",
+		"changed: com.example.c 1.0 -> 1.1",
+		"changed: com.example.d 1.0 -> 1.0",
+		"added: com.example.e 2.0",
+	}
+	var got []string
+	for _, c := range DiffPackages(old, new) {
+		got = append(got, c.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("DiffPackages() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", want, got)
+	}
+}
+
+// Testing repos are built from explicit directories, concurrently and without touching the current directory.
+func TestBuilder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "afto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := filepath.Join(testData, "deb", "com.yourcompany.tweakexample_0.0.1-2_iphoneos-arm.deb")
+	cwd, _ := ioutil.ReadDir(".")
+
+	cfg := DefaultConfig()
+	cfg.Compressions = []string{"gz"}
+	repos := []string{filepath.Join(dir, "one"), filepath.Join(dir, "two")}
+	errs := make(chan error, len(repos))
+	for _, repo := range repos {
+		go func(repo string) {
+			b := NewBuilder(repo, cfg)
+			b.AddDeb(src)
+			b.SetIndex(func(debs []string) []byte { return []byte(strings.Join(debs, "\n")) })
+			_, err := b.Build()
+			errs <- err
+		}(repo)
+	}
+	for range repos {
+		if err := <-errs; err != nil {
+			t.Fatalf("Build() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", nil, err)
+		}
+	}
+	for _, repo := range repos {
+		for _, name := range []string{"Packages", "Packages.gz", "Release", "index.html", filepath.Base(src)} {
+			if _, err := os.Stat(filepath.Join(repo, name)); err != nil {
+				t.Errorf("Build(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", repo, name, err)
+			}
+		}
+		if report, err := VerifyRepo(repo, nil); err != nil || !report.OK {
+			t.Errorf("Build(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" (%v) \n\n", repo, "verified repo", report, err)
+		}
+	}
+	if after, _ := ioutil.ReadDir("."); len(after) != len(cwd) {
+		t.Errorf("Build() failed test. \n\n\rWant: \n\r\"%d files in the current directory\" \n\rGot: \n\r\"%d\" \n\n", len(cwd), len(after))
+	}
+
+	// Removing the deb leaves an empty repo, and reports the package as removed.
+	b := NewBuilder(repos[0], cfg)
+	b.RemoveDeb(filepath.Base(src))
+	result, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", nil, err)
+	}
+	if len(result.Debs) != 0 || len(result.Changes) != 1 || result.Changes[0].Kind != ChangeRemoved {
+		t.Errorf("Build() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", "removed: com.yourcompany.tweakexample", result.Changes)
+	}
+	if _, err := os.Stat(filepath.Join(repos[0], filepath.Base(src))); !os.IsNotExist(err) {
+		t.Errorf("Build() failed test. the removed deb is still in the repo.")
+	}
+
+	// Two debs added with the same name fail the build, rather than one replacing the other.
+	other := filepath.Join(dir, "other", filepath.Base(src))
+	os.MkdirAll(filepath.Dir(other), 0755)
+	Copy(src, other)
+	b = NewBuilder(repos[1], cfg)
+	b.AddDeb(src)
+	b.AddDeb(src)
+	b.AddDeb(other)
+	if _, err := b.Build(); err == nil || err.Error() != src+" and "+other+" are both added as "+filepath.Base(src) {
+		t.Errorf("Build() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", "*DuplicateDebError", err)
+	}
+}
+
+// Testing the pool path of packages.
+func TestPoolPath(t *testing.T) {
+	var paramTests = []struct {
+		pkg  string
+		want string
+	}{
+		{"com.example.Tweak", "pool/main/c/com.example.tweak/a.deb"},
+		{"libfoo", "pool/main/libf/libfoo/a.deb"},
+		{"lib", "pool/main/l/lib/a.deb"},
+	}
+	for _, p := range paramTests {
+		if got := PoolPath("main", p.pkg, "a.deb"); got != p.want {
+			t.Errorf("PoolPath(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", p.pkg, p.want, got)
+		}
+	}
+}
+
+// Testing the pool layout moves debs into pool/ and writes per architecture indexes under dists/,
+// and going back to the flat layout moves them out again.
+func TestBuilderPool(t *testing.T) {
+	dir, err := ioutil.TempDir("", "afto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := filepath.Join(testData, "deb", "com.yourcompany.tweakexample_0.0.1-2_iphoneos-arm.deb")
+	Copy(src, filepath.Join(dir, filepath.Base(src)))
+
+	cfg := DefaultConfig()
+	cfg.Layout = LayoutPool
+	cfg.Architectures = []string{"iphoneos-arm", "iphoneos-arm64"}
+	cfg.Compressions = []string{"gz"}
+	if _, err := NewBuilder(dir, cfg).Build(); err != nil {
+		t.Fatalf("Build() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", nil, err)
+	}
+	pooled := "pool/main/c/com.yourcompany.tweakexample/" + filepath.Base(src)
+	for _, name := range []string{pooled, "Packages", "Release", "dists/beta/Release",
+		"dists/beta/main/binary-iphoneos-arm/Packages.gz", "dists/beta/main/binary-iphoneos-arm64/Packages"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Build() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, filepath.Base(src))); !os.IsNotExist(err) {
+		t.Errorf("Build() failed test. the deb was not moved into pool/.")
+	}
+	entries, err := LoadPackages(dir)
+	if err != nil || len(entries) != 1 || entries[0].Filename() != pooled {
+		t.Errorf("Build() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", pooled, err)
+	}
+	arm64, _ := ioutil.ReadFile(filepath.Join(dir, "dists/beta/main/binary-iphoneos-arm64/Packages"))
+	if len(arm64) != 0 {
+		t.Errorf("Build() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", "no iphoneos-arm64 packages", arm64)
+	}
+	if report, err := VerifyRepo(dir, nil); err != nil || !report.OK {
+		t.Errorf("Build() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" (%v) \n\n", "verified repo", report, err)
+	}
+
+	cfg.Layout = LayoutFlat
+	if _, err := NewBuilder(dir, cfg).Build(); err != nil {
+		t.Fatalf("Build() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", nil, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, filepath.Base(src))); err != nil {
+		t.Errorf("Build() failed test. the deb was not moved out of pool/. (%v)", err)
+	}
+	for _, name := range []string{pooled, "dists/beta/Release"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("Build() failed test. \n\n\rWant: \n\r\"%s removed\" \n\rGot: \n\r\"%v\" \n\n", name, err)
+		}
+	}
+}
+
+// Testing the pool layout generates Packages files per component and architecture.
+func TestBuilderComponents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "afto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := filepath.Join(testData, "deb", "com.yourcompany.tweakexample_0.0.1-2_iphoneos-arm.deb")
+	Copy(src, filepath.Join(dir, filepath.Base(src)))
+
+	cfg := DefaultConfig()
+	cfg.Layout = LayoutPool
+	cfg.Components = []string{"main", "beta"}
+	cfg.Architectures = []string{"iphoneos-arm64"}
+	cfg.Compressions = []string{"gz"}
+
+	b := NewBuilder(dir, cfg)
+	b.SetComponent("testing")
+	if _, err := b.Build(); err == nil {
+		t.Errorf("Build() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", "unknown component error", err)
+	}
+
+	b = NewBuilder(dir, cfg)
+	b.SetComponent("beta")
+	result, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", nil, err)
+	}
+	archs := strings.Join(result.Architectures, ", ")
+	if archs != "iphoneos-arm64, iphoneos-arm" {
+		t.Errorf("Build() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", "iphoneos-arm64, iphoneos-arm", archs)
+	}
+	pooled := "pool/beta/c/com.yourcompany.tweakexample/" + filepath.Base(src)
+	var paramTests = []struct {
+		name     string
+		packages bool
+	}{
+		{"dists/beta/main/binary-iphoneos-arm/Packages", false},
+		{"dists/beta/main/binary-iphoneos-arm64/Packages", false},
+		{"dists/beta/beta/binary-iphoneos-arm/Packages", true},
+		{"dists/beta/beta/binary-iphoneos-arm64/Packages", false},
+	}
+	release, _ := ioutil.ReadFile(filepath.Join(dir, "dists/beta/Release"))
+	for _, p := range paramTests {
+		data, err := ioutil.ReadFile(filepath.Join(dir, p.name))
+		if err != nil {
+			t.Errorf("Build() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", p.name, err)
+			continue
+		}
+		if got := strings.Contains(string(data), "Filename: "+pooled); got != p.packages {
+			t.Errorf("Build() failed test. \n\n\rWant: \n\r\"%s holds the deb: %v\" \n\rGot: \n\r\"%v\" \n\n", p.name, p.packages, got)
+		}
+		if name := strings.TrimPrefix(p.name, "dists/beta/"); !strings.Contains(string(release), " "+name+"\n") {
+			t.Errorf("Build() failed test. \n\n\rWant: \n\r\"%s in Release\" \n\rGot: \n\r\"%s\" \n\n", name, release)
+		}
+	}
+	for _, field := range []string{"Components: main beta\n", "Architectures: iphoneos-arm64 iphoneos-arm\n"} {
+		if !strings.Contains(string(release), field) {
+			t.Errorf("Build() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", field, release)
+		}
+	}
+}
+
+// writeDeb writes a deb holding only the control file control to path.
+func writeDeb(t *testing.T, path string, control string) {
+	var tarball bytes.Buffer
+	tw := tar.NewWriter(&tarball)
+	tw.WriteHeader(&tar.Header{Name: "./control", Mode: 0644, Size: int64(len(control))})
+	tw.Write([]byte(control))
+	tw.Close()
+	var empty bytes.Buffer
+	tar.NewWriter(&empty).Close()
+
+	ar := bytes.NewBufferString("!<arch>\n")
+	for _, m := range []struct {
+		name string
+		data []byte
+	}{{"debian-binary", []byte("2.0\n")}, {"control.tar", tarball.Bytes()}, {"data.tar", empty.Bytes()}} {
+		size := strconv.Itoa(len(m.data))
+		ar.WriteString(m.name + strings.Repeat(" ", 16-len(m.name)) + "0           0     0     100644  " + size + strings.Repeat(" ", 10-len(size)) + "`\n")
+		ar.Write(m.data)
+		if len(m.data)%2 == 1 {
+			ar.WriteString("\n")
+		}
+	}
+	if err := ioutil.WriteFile(path, ar.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Testing the retention policy keeps the newest versions of every package.
+func TestExpiredDebs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "afto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	now := time.Now()
+	for i, v := range []string{"1.0", "1.1", "1.2~beta1", "1.2"} {
+		name := "com.example.tweak_" + v + "_iphoneos-arm.deb"
+		writeDeb(t, filepath.Join(dir, name), "Package: com.example.tweak\nVersion: "+v+"\nArchitecture: iphoneos-arm\n")
+		// Older versions were modified earlier. (1.0 four days ago)
+		age := now.Add(-time.Duration(4-i) * 24 * time.Hour)
+		os.Chtimes(filepath.Join(dir, name), age, age)
+	}
+	writeDeb(t, filepath.Join(dir, "other_0.1.deb"), "Package: other\nVersion: 0.1\nArchitecture: all\n")
+	// Newer versions for other architectures do not expire the ones of iphoneos-arm.
+	writeDeb(t, filepath.Join(dir, "com.example.tweak_1.3_iphoneos-arm64.deb"), "Package: com.example.tweak\nVersion: 1.3\nArchitecture: iphoneos-arm64\n")
+	writeDeb(t, filepath.Join(dir, "com.example.tweak_1.4_all.deb"), "Package: com.example.tweak\nVersion: 1.4\nArchitecture: all\n")
+	debs, err := NewBuilder(dir, DefaultConfig()).Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paramTests = []struct {
+		keep    int
+		keepFor time.Duration
+		pkg     string
+		want    string
+	}{
+		{1, 0, "", "com.example.tweak_1.0_iphoneos-arm.deb com.example.tweak_1.1_iphoneos-arm.deb com.example.tweak_1.2~beta1_iphoneos-arm.deb"},
+		{2, 0, "", "com.example.tweak_1.0_iphoneos-arm.deb com.example.tweak_1.1_iphoneos-arm.deb"},
+		{1, 60 * time.Hour, "", "com.example.tweak_1.0_iphoneos-arm.deb com.example.tweak_1.1_iphoneos-arm.deb"},
+		{5, 0, "", ""},
+		{1, 0, "other", ""},
+	}
+	for _, p := range paramTests {
+		cfg := DefaultConfig()
+		cfg.KeepVersions, cfg.KeepFor = p.keep, p.keepFor
+		expired, err := ExpiredDebs(debs, cfg, p.pkg, now)
+		var names []string
+		for _, d := range expired {
+			names = append(names, d.Name)
+		}
+		if got := strings.Join(names, " "); err != nil || got != p.want {
+			t.Errorf("ExpiredDebs(%d, %v, %q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" (%v) \n\n", p.keep, p.keepFor, p.pkg, p.want, got, err)
+		}
+	}
+}
+
+// Testing packages are summarized and selected by filters.
+func TestPackageFilter(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join(testData, "packages", "Packages"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := deb.NewPackages().ParseString(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := Summarize(p); s.Package != "com.yourcompany.tweakexample" || s.Size != 2166 || s.Architecture != "iphoneos-arm" {
+		t.Errorf("Summarize() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", "com.yourcompany.tweakexample 2166 iphoneos-arm", s)
+	}
+
+	var paramTests = []struct {
+		filter PackageFilter
+		want   bool
+	}{
+		{PackageFilter{}, true},
+		{PackageFilter{Pattern: "com.yourcompany.*"}, true},
+		{PackageFilter{Pattern: "TWEAK"}, true},
+		{PackageFilter{Pattern: "com.example.*"}, false},
+		{PackageFilter{Architecture: "iphoneos-arm"}, true},
+		{PackageFilter{Architecture: "iphoneos-arm64"}, false},
+		{PackageFilter{Section: p.Section()}, true},
+		{PackageFilter{Section: "Themes"}, false},
+	}
+	for _, f := range paramTests {
+		if got := f.filter.Match(p); got != f.want {
+			t.Errorf("Match(%v) failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", f.filter, f.want, got)
+		}
+	}
+	if fields := Fields(p); fields["Package"] != p.Package() || fields["Size"] != "2166" {
+		t.Errorf("Fields() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", "Package and Size fields", fields)
+	}
+}
+
+// Testing the repos under a directory are found and their stale indexes reported.
+func TestWalkRepos(t *testing.T) {
+	root, err := ioutil.TempDir("", "afto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	src := filepath.Join(testData, "deb", "com.yourcompany.tweakexample_0.0.1-2_iphoneos-arm.deb")
+	for _, dir := range []string{"flat", "suites/beta"} {
+		b := NewBuilder(filepath.Join(root, dir), DefaultConfig())
+		b.AddDeb(src)
+		if _, err := b.Build(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.MkdirAll(filepath.Join(root, "empty", ".hidden"), 0755)
+
+	repos, err := WalkRepos(root)
+	if err != nil || len(repos) != 2 {
+		t.Fatalf("WalkRepos(%q) failed test. \n\n\rWant: \n\r\"%d\" repos \n\rGot: \n\r\"%d\" (%v) \n\n", root, 2, len(repos), err)
+	}
+	for _, r := range repos {
+		if r.Packages != 1 || r.Debs != 1 || r.Stale || r.Signature != SignatureNone || r.Generated.IsZero() {
+			t.Errorf("WalkRepos(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", root, "1 package, up to date, unsigned", r)
+		}
+	}
+
+	// A deb copied again, but not changed, is not stale. (removed first, it may be a link to src)
+	flatDeb := filepath.Join(root, "flat", filepath.Base(src))
+	os.Remove(flatDeb)
+	Copy(src, flatDeb)
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(flatDeb, later, later)
+	if r, err := RepoState(filepath.Join(root, "flat")); err != nil || r.Stale {
+		t.Errorf("RepoState(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" (%v) \n\n", "flat", "up to date", r, err)
+	}
+
+	// A deb copied in without regenerating and a deb removed make the indexes stale.
+	Copy(src, filepath.Join(root, "flat", "extra.deb"))
+	os.Remove(filepath.Join(root, "suites", "beta", filepath.Base(src)))
+	repos, err = WalkRepos(root)
+	if err != nil || len(repos) != 2 {
+		t.Fatalf("WalkRepos(%q) failed test. \n\n\rWant: \n\r\"%d\" repos \n\rGot: \n\r\"%d\" (%v) \n\n", root, 2, len(repos), err)
+	}
+	var paramTests = []struct {
+		status *RepoStatus
+		want   string
+	}{
+		{repos[0], "extra.deb is not in Packages"},
+		{repos[1], "com.yourcompany.tweakexample_0.0.1-2_iphoneos-arm.deb is in Packages but does not exist"},
+	}
+	for _, p := range paramTests {
+		if !p.status.Stale || strings.Join(p.status.Reasons, "; ") != p.want {
+			t.Errorf("WalkRepos(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", p.status.Dir, p.want, p.status.Reasons)
+		}
+	}
+	// A deb replaced by another of the same size is stale.
+	data, _ := ioutil.ReadFile(flatDeb)
+	data[len(data)-1] ^= 0xff
+	ioutil.WriteFile(flatDeb, data, 0644)
+	later = later.Add(time.Hour)
+	os.Chtimes(flatDeb, later, later)
+	want := filepath.Base(src) + " changed since Packages was generated; extra.deb is not in Packages"
+	if r, err := RepoState(filepath.Join(root, "flat")); err != nil || strings.Join(r.Reasons, "; ") != want {
+		t.Errorf("RepoState(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" (%v) \n\n", "flat", want, r, err)
+	}
+}
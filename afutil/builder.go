@@ -0,0 +1,711 @@
+package afutil
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/hako/afto/deb"
+)
+
+// Icons are the file names of the repo icons shown by Cydia.
+var Icons = []string{"CydiaIcon.png", "CydiaIcon@2x.png", "CydiaIcon@3x.png"}
+
+// Builder builds the index files of a repo directory. The debs of the repo are the ones already in it,
+// plus the ones added from other directories, minus the ones removed.
+// Every file is written to a staging directory inside the repo and published at once,
+// so a Builder never reads or writes the current directory and builds of other repos can run alongside.
+type Builder struct {
+	dir       string
+	config    *Config
+	key       *openpgp.Entity
+	cache     *Cache
+	assets    func(name string) ([]byte, error)
+	index     func(debs []string) []byte
+	component string
+	suite     string
+
+	added    map[string]string
+	moved    map[string]bool
+	removed  map[string]bool
+	packages map[string]string // the package of the debs poolName read, by path.
+
+	// duplicate is set when two debs added have the same name, building then fails.
+	duplicate *DuplicateDebError
+}
+
+// DuplicateDebError is returned when building a repo to which two debs of the same name were added,
+// as only one of them could be published. (a/tweak.deb and b/tweak.deb)
+type DuplicateDebError struct {
+	Name  string
+	Path  string
+	Other string
+}
+
+// Error returns the debs added with the same name.
+func (e *DuplicateDebError) Error() string {
+	return e.Other + " and " + e.Path + " are both added as " + e.Name
+}
+
+// BuildResult represents what a Builder did to a repo.
+type BuildResult struct {
+	Debs         []DebFile
+	Read         int
+	Cached       int
+	Compressions []string
+	Signed       bool
+	Unsigned     bool // the Release files were signed, but there was no key so their signatures were removed.
+	ByHash       bool
+	Changes      []PackageChange
+
+	// Components and Architectures are the ones listed in the Release files.
+	Components    []string
+	Architectures []string
+}
+
+// NewBuilder creates a new Builder for the repo dir, configured by cfg.
+func NewBuilder(dir string, cfg *Config) *Builder {
+	return &Builder{
+		dir:      dir,
+		config:   cfg,
+		added:    map[string]string{},
+		moved:    map[string]bool{},
+		removed:  map[string]bool{},
+		packages: map[string]string{},
+	}
+}
+
+// Dir returns the repo directory of the Builder.
+func (b *Builder) Dir() string {
+	return b.dir
+}
+
+// SetKey sets the key signing the Release file. The repo is not signed when key is nil.
+func (b *Builder) SetKey(key *openpgp.Entity) {
+	b.key = key
+}
+
+// SetCache sets the package metadata cache. By default the cache of the repo is loaded.
+func (b *Builder) SetCache(cache *Cache) {
+	b.cache = cache
+}
+
+// SetAssets sets the function returning the default icons, used for the icons the config does not set.
+func (b *Builder) SetAssets(assets func(name string) ([]byte, error)) {
+	b.assets = assets
+}
+
+// SetIndex sets the function generating index.html from the deb file names of the repo.
+// No index.html is written when it is not set.
+func (b *Builder) SetIndex(index func(debs []string) []byte) {
+	b.index = index
+}
+
+// SetComponent sets the component the added debs go to in the pool layout.
+// By default they go to the first component of the config.
+func (b *Builder) SetComponent(component string) {
+	b.component = component
+}
+
+// SetSuite sets the suite built when the config lists suites. By default the default suite is built.
+// Every suite has its own Packages and Release files under dists/, and they share the debs of pool/.
+// The default suite also has the root ones, and holds the debs no suite lists, such as debs copied into the repo.
+func (b *Builder) SetSuite(suite string) {
+	b.suite = suite
+}
+
+// suiteName returns the suite the Builder builds.
+func (b *Builder) suiteName() string {
+	if b.suite != "" {
+		return b.suite
+	}
+	return b.config.Suite
+}
+
+// isDefault returns whether the Builder builds the default suite, which has the root index files.
+func (b *Builder) isDefault() bool {
+	return len(b.config.Suites) == 0 || b.suiteName() == b.config.Suite
+}
+
+// AddDeb adds the deb at path to the repo, replacing the deb of the same name in the repo.
+// The deb is copied into the repo when it is published. Adding another deb of the same name
+// makes building fail with a DuplicateDebError.
+func (b *Builder) AddDeb(path string) {
+	name := filepath.Base(path)
+	if other, ok := b.added[name]; ok && filepath.Clean(other) != filepath.Clean(path) && b.duplicate == nil {
+		b.duplicate = &DuplicateDebError{Name: name, Path: path, Other: other}
+	}
+	b.added[name] = path
+	delete(b.moved, name)
+}
+
+// MoveDeb is like AddDeb, but moves the deb into the repo. It is moved back when building fails.
+func (b *Builder) MoveDeb(path string) {
+	b.AddDeb(path)
+	b.moved[filepath.Base(path)] = true
+}
+
+// RemoveDeb removes the deb name from the repo. (com.example.tweakexample_1.0_iphoneos-arm.deb)
+// In the pool layout, name is the path of the deb in the repo. (pool/main/c/com.example.tweakexample/...)
+func (b *Builder) RemoveDeb(name string) {
+	b.removed[name] = true
+	delete(b.added, path.Base(name))
+	delete(b.moved, path.Base(name))
+	if b.duplicate != nil && b.duplicate.Name == path.Base(name) {
+		b.duplicate = nil
+	}
+}
+
+// Debs returns the debs the repo will hold once built, sorted by name.
+// The Path of a deb is where it is now, which is outside the repo for added debs.
+func (b *Builder) Debs() ([]DebFile, error) {
+	p, err := b.plan()
+	if err != nil {
+		return nil, err
+	}
+	return p.debs, nil
+}
+
+// DebEntry represents a deb the repo will hold and its Packages entry.
+type DebEntry struct {
+	DebFile
+	Entry *deb.Packages
+}
+
+// Entries returns the debs the repo will hold once built with their Packages entries, sorted by name.
+// The entries come from the cache of the repo, so only the debs which are new or changed are read.
+func (b *Builder) Entries() ([]DebEntry, error) {
+	debs, err := b.Debs()
+	if err != nil {
+		return nil, err
+	}
+	b.loadCache()
+	var entries []DebEntry
+	for _, d := range debs {
+		scanned, err := scanDebCached(d.Path, d.Name, b.cache)
+		if err != nil {
+			return nil, &DebError{Name: d.Name, Err: err}
+		}
+		entry, err := deb.NewPackages().ParseString(scanned.para.String())
+		if err != nil {
+			return nil, &DebError{Name: d.Name, Err: err}
+		}
+		entries = append(entries, DebEntry{DebFile: d, Entry: entry})
+	}
+	return entries, nil
+}
+
+// loadCache loads the cache of the repo, unless a cache is set.
+func (b *Builder) loadCache() {
+	if b.cache == nil {
+		b.cache = LoadCache(filepath.Join(b.dir, CacheFile))
+	}
+}
+
+// debSource represents where a deb of the repo comes from when it is not in place yet.
+type debSource struct {
+	path string
+	move bool
+}
+
+// buildPlan represents the debs of a repo once built, the debs to stage and the debs to remove.
+type buildPlan struct {
+	debs    []DebFile
+	sources map[string]debSource
+	remove  []string
+	shared  map[string]bool // the debs other suites list, which are never removed.
+}
+
+// plan works out the debs of the repo once built. Debs in the repo which are not where
+// the layout of the config puts them are moved there. (from the repo root into pool/, or back)
+// A repo which a crash left half published is recovered first.
+func (b *Builder) plan() (*buildPlan, error) {
+	if b.duplicate != nil {
+		return nil, b.duplicate
+	}
+	if err := RecoverStaging(b.dir); err != nil {
+		return nil, err
+	}
+	existing, shared, err := b.existingDebs()
+	if err != nil {
+		return nil, err
+	}
+	p := &buildPlan{sources: map[string]debSource{}, shared: shared}
+	debs := map[string]string{}
+	for _, name := range existing {
+		if b.removed[name] {
+			continue
+		}
+		src := filepath.Join(b.dir, filepath.FromSlash(name))
+		target := name
+		switch {
+		case b.config.Layout == LayoutPool && !strings.HasPrefix(name, "pool/"):
+			if target, err = b.poolName(src); err != nil {
+				return nil, err
+			}
+		case b.config.Layout != LayoutPool && strings.HasPrefix(name, "pool/"):
+			target = path.Base(name)
+		}
+		if b.removed[target] {
+			continue
+		}
+		if target != name {
+			p.sources[target] = debSource{path: src}
+		}
+		debs[target] = src
+	}
+	for base, src := range b.added {
+		target := base
+		if b.config.Layout == LayoutPool {
+			if target, err = b.poolName(src); err != nil {
+				return nil, err
+			}
+		}
+		p.sources[target] = debSource{path: src, move: b.moved[base]}
+		debs[target] = src
+	}
+
+	for name, src := range debs {
+		p.debs = append(p.debs, DebFile{Path: src, Name: name})
+	}
+	sort.Slice(p.debs, func(i, j int) bool {
+		return p.debs[i].Name < p.debs[j].Name
+	})
+	for _, name := range existing {
+		if _, ok := debs[name]; !ok && !shared[name] {
+			p.remove = append(p.remove, name)
+		}
+	}
+	return p, nil
+}
+
+// existingDebs returns the names of the debs of the suite built, and the debs the other suites list.
+// Without suites in the config, the debs are the ones in the repo root and in pool/.
+func (b *Builder) existingDebs() ([]string, map[string]bool, error) {
+	names, err := b.repoDebs()
+	if err != nil || len(b.config.Suites) == 0 {
+		return names, nil, err
+	}
+	shared := map[string]bool{}
+	listed := map[string]bool{}
+	for _, suite := range b.config.Suites {
+		entries, err := LoadSuitePackages(b.dir, suite)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, e := range entries {
+			if suite == b.suiteName() {
+				listed[debName(e)] = true
+			} else {
+				shared[debName(e)] = true
+			}
+		}
+	}
+	var existing []string
+	for _, name := range names {
+		if listed[name] || b.isDefault() && !shared[name] {
+			existing = append(existing, name)
+		}
+	}
+	return existing, shared, nil
+}
+
+// loadPackages reads the entries of the Packages files of the suite built.
+func (b *Builder) loadPackages() ([]*deb.Packages, error) {
+	if len(b.config.Suites) > 0 {
+		return LoadSuitePackages(b.dir, b.suiteName())
+	}
+	return LoadPackages(b.dir)
+}
+
+// repoDebs returns the names of the debs in the repo root and in pool/.
+func (b *Builder) repoDebs() ([]string, error) {
+	var names []string
+	files, err := ioutil.ReadDir(b.dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, f := range files {
+		if !f.IsDir() && filepath.Ext(f.Name()) == ".deb" {
+			names = append(names, f.Name())
+		}
+	}
+	pool := filepath.Join(b.dir, "pool")
+	if _, err := os.Stat(pool); err != nil {
+		return names, nil
+	}
+	err = filepath.Walk(pool, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && filepath.Ext(p) == ".deb" {
+			rel, _ := filepath.Rel(b.dir, p)
+			names = append(names, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return names, err
+}
+
+// poolName returns the path of the deb at src in the pool of the component of added debs.
+// The package of every deb is only read once.
+func (b *Builder) poolName(src string) (string, error) {
+	pkg, ok := b.packages[src]
+	if !ok {
+		control, err := ParseDeb(src)
+		if err != nil {
+			return "", errors.New(src + ": " + err.Error())
+		}
+		if control.Package() == "" {
+			return "", errors.New(src + ": no Package field in control file")
+		}
+		pkg = control.Package()
+		b.packages[src] = pkg
+	}
+	component := b.component
+	if component == "" {
+		component = b.config.Components[0]
+	}
+	return PoolPath(component, pkg, filepath.Base(src)), nil
+}
+
+// Build generates the index files of the repo and publishes them together with the added debs.
+// The repo is left untouched when anything fails.
+func (b *Builder) Build() (*BuildResult, error) {
+	if b.config == nil {
+		return nil, errors.New("builder has no config")
+	}
+	if b.component != "" && !contains(b.config.Components, b.component) {
+		return nil, errors.New("component \"" + b.component + "\" is not one of the components of the config. (" + strings.Join(b.config.Components, ", ") + ")")
+	}
+	if len(b.config.Suites) > 0 && (!b.config.HasSuite(b.suiteName()) || b.config.Layout != LayoutPool) {
+		return nil, errors.New("suite \"" + b.suiteName() + "\" is not one of the suites of the config, in the pool layout. (" + strings.Join(b.config.Suites, ", ") + ")")
+	}
+	if err := os.MkdirAll(b.dir, 0755); err != nil {
+		return nil, err
+	}
+	p, err := b.plan()
+	if err != nil {
+		return nil, err
+	}
+	b.loadCache()
+	old, _ := b.loadPackages()
+
+	staging, err := NewStaging(b.dir)
+	if err != nil {
+		return nil, err
+	}
+	result, err := b.stage(staging, p)
+	if err != nil {
+		staging.Abort()
+		return nil, err
+	}
+	if err := staging.Publish(); err != nil {
+		staging.Abort()
+		return nil, err
+	}
+
+	current, err := b.loadPackages()
+	if err != nil {
+		return nil, err
+	}
+	result.Changes = DiffPackages(old, current)
+	return result, nil
+}
+
+// stage writes the debs which are not in place yet and every index file of the repo into staging.
+func (b *Builder) stage(staging *Staging, p *buildPlan) (*BuildResult, error) {
+	result := &BuildResult{Compressions: b.config.Compressions}
+
+	// New and moved debs go through the staging directory, so they appear together with the index files.
+	debs := p.debs
+	for i, d := range debs {
+		src, ok := p.sources[d.Name]
+		if !ok {
+			continue
+		}
+		var err error
+		if src.move {
+			err = staging.Adopt(src.path, d.Name)
+		} else {
+			err = linkOrCopy(src.path, staging.Path(d.Name))
+		}
+		if err != nil {
+			return nil, err
+		}
+		debs[i].Path = staging.Path(d.Name)
+	}
+	for _, name := range p.remove {
+		staging.Remove(name)
+	}
+	result.Debs = debs
+
+	// Packages file listing every deb, and the Release file signing it.
+	entries, err := scanDebFiles(debs, b.cache)
+	if err != nil {
+		return nil, err
+	}
+	// The debs read before, checking the dependencies of the repo, count as read rather than cached.
+	for _, d := range debs {
+		if b.cache.read[d.Name] {
+			result.Read++
+		} else {
+			result.Cached++
+		}
+	}
+	result.Components, result.Architectures = b.components(entries), b.architectures(entries)
+	cfg := *b.config
+	cfg.Components, cfg.Architectures = result.Components, result.Architectures
+	cfg.Suite = b.suiteName()
+	result.Signed = b.key != nil
+	result.Unsigned = b.key == nil && b.signed()
+	result.ByHash = b.config.AcquireByHash
+
+	// The pool layout also has Packages files per architecture under dists/, the flat one has none.
+	if b.config.Layout == LayoutPool {
+		if err := b.stageDists(staging, &cfg, entries); err != nil {
+			return nil, err
+		}
+	} else if err := b.removeStale(staging, "dists"); err != nil {
+		return nil, err
+	}
+	// The cache is only an optimisation, a repo is still built when it cannot be saved.
+	// The debs of the other suites stay in it.
+	for name := range p.shared {
+		b.cache.keep(name)
+	}
+	b.cache.Save(staging.Path(CacheFile))
+
+	// Only the default suite has the root index files, icons and index.html.
+	if !b.isDefault() {
+		return result, nil
+	}
+
+	if err := b.stageIndexes(staging, "", entries); err != nil {
+		return nil, err
+	}
+	rfile, err := ReleaseFile(staging.Dir(), &cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.stageRelease(staging, "", rfile); err != nil {
+		return nil, err
+	}
+
+	// Icons of the config, or else the default ones.
+	icons := map[string]string{
+		Icons[0]: b.config.Icon,
+		Icons[1]: b.config.Icon2x,
+		Icons[2]: b.config.Icon3x,
+	}
+	for _, name := range Icons {
+		if icons[name] != "" {
+			if err := Copy(icons[name], staging.Path(name)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if b.assets == nil {
+			continue
+		}
+		data, err := b.assets(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := staging.WriteFile(name, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if b.index != nil {
+		var names []string
+		for _, d := range debs {
+			names = append(names, d.Name)
+		}
+		if err := staging.WriteFile("index.html", b.index(names)); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// stageIndexes writes the Packages file holding entries into the directory dir of staging,
+// with its compressed variants and by-hash copies. Compressions no longer configured are removed.
+func (b *Builder) stageIndexes(staging *Staging, dir string, entries []scanEntry) error {
+	if err := staging.WriteFile(path.Join(dir, "Packages"), formatEntries(entries)); err != nil {
+		return err
+	}
+	if err := CompressPackages(staging.Path(dir), b.config.Compressions); err != nil {
+		return err
+	}
+	for _, p := range PackagesFiles(Compressions) {
+		if _, err := os.Stat(staging.Path(path.Join(dir, p))); os.IsNotExist(err) {
+			staging.Remove(path.Join(dir, p))
+		}
+	}
+	if b.config.AcquireByHash {
+		return ByHash(staging.Path(dir), append([]string{"Packages"}, PackagesFiles(b.config.Compressions)...))
+	}
+	return nil
+}
+
+// signed returns whether the Release files the Builder writes are signed now.
+func (b *Builder) signed() bool {
+	dirs := []string{DistsDir(b.suiteName())}
+	if b.isDefault() {
+		dirs = append(dirs, "")
+	}
+	for _, d := range dirs {
+		if signedDir(filepath.Join(b.dir, filepath.FromSlash(d))) {
+			return true
+		}
+	}
+	return false
+}
+
+// stageRelease writes the Release file rfile into the directory dir of staging and signs it.
+// Old signatures would not match the new Release file, so they are removed when there is no key.
+// (BuildResult.Unsigned)
+func (b *Builder) stageRelease(staging *Staging, dir string, rfile string) error {
+	if err := staging.WriteFile(path.Join(dir, "Release"), []byte(rfile)); err != nil {
+		return err
+	}
+	if b.key != nil {
+		return SignRelease(staging.Path(dir), b.key)
+	}
+	staging.Remove(path.Join(dir, "Release.gpg"))
+	staging.Remove(path.Join(dir, "InRelease"))
+	return nil
+}
+
+// stageDists writes the Packages files of every component and architecture in cfg and the Release file
+// of the suite under dists/, for APT clients. (dists/beta/main/binary-iphoneos-arm/Packages)
+// Debs of the architecture "all" are listed for every architecture.
+func (b *Builder) stageDists(staging *Staging, cfg *Config, entries []scanEntry) error {
+	dists := DistsDir(cfg.Suite)
+	var indexes []string
+	for _, component := range cfg.Components {
+		for _, arch := range cfg.Architectures {
+			var archEntries []scanEntry
+			for _, e := range entries {
+				a := e.para.Get("Architecture")
+				if entryComponent(e) == component && (a == arch || a == "all") {
+					archEntries = append(archEntries, e)
+				}
+			}
+			dir := BinaryDir(component, arch)
+			if err := b.stageIndexes(staging, path.Join(dists, dir), archEntries); err != nil {
+				return err
+			}
+			for _, name := range append([]string{"Packages"}, PackagesFiles(cfg.Compressions)...) {
+				indexes = append(indexes, path.Join(dir, name))
+			}
+		}
+	}
+	rfile, err := ReleaseFileFor(staging.Path(dists), cfg, indexes)
+	if err != nil {
+		return err
+	}
+	if err := b.stageRelease(staging, dists, rfile); err != nil {
+		return err
+	}
+	return b.removeStale(staging, dists)
+}
+
+// entryComponent returns the component of a Packages entry from its pool Filename,
+// or an empty string when the deb is not in the pool.
+func entryComponent(e scanEntry) string {
+	parts := strings.Split(e.para.Get("Filename"), "/")
+	if len(parts) < 3 || parts[0] != "pool" {
+		return ""
+	}
+	return parts[1]
+}
+
+// components returns the components of the config, followed by the other components of the pool
+// holding entries, so no deb is left out of dists/. The flat layout has the components of the config.
+func (b *Builder) components(entries []scanEntry) []string {
+	components := append([]string{}, b.config.Components...)
+	if b.config.Layout != LayoutPool {
+		return components
+	}
+	var extra []string
+	for _, e := range entries {
+		if c := entryComponent(e); c != "" && !contains(components, c) && !contains(extra, c) {
+			extra = append(extra, c)
+		}
+	}
+	sort.Strings(extra)
+	return append(components, extra...)
+}
+
+// architectures returns the architectures of the config, followed by the other architectures
+// of the entries in the pool layout. Debs of the architecture "all" do not add one.
+// The flat layout has the architecture of the config, as it has no index for any other.
+func (b *Builder) architectures(entries []scanEntry) []string {
+	archs := append([]string{}, b.config.Architectures...)
+	if b.config.Layout != LayoutPool {
+		return archs
+	}
+	var extra []string
+	for _, e := range entries {
+		if a := e.para.Get("Architecture"); a != "" && a != "all" && !contains(archs, a) && !contains(extra, a) {
+			extra = append(extra, a)
+		}
+	}
+	sort.Strings(extra)
+	return append(archs, extra...)
+}
+
+// contains returns whether list holds s.
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// removeStale removes the files of the repo directory dir which were not staged,
+// such as the Packages files of an architecture no longer configured.
+// by-hash files are kept, as clients may still be fetching them, unless the whole of dists/ goes.
+func (b *Builder) removeStale(staging *Staging, dir string) error {
+	root := filepath.Join(b.dir, filepath.FromSlash(dir))
+	if _, err := os.Stat(root); err != nil {
+		return nil
+	}
+	return filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(b.dir, p)
+		name := filepath.ToSlash(rel)
+		if info.IsDir() {
+			if info.Name() == "by-hash" && dir != "dists" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if _, err := os.Stat(staging.Path(name)); os.IsNotExist(err) {
+			staging.Remove(name)
+		}
+		return nil
+	})
+}
+
+// linkOrCopy hard links the file src to dst, or copies it when it cannot be linked.
+func linkOrCopy(src string, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+	return Copy(src, dst)
+}
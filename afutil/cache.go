@@ -0,0 +1,161 @@
+package afutil
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/hako/afto/deb"
+)
+
+// CacheFile is the name of the package metadata cache kept in a repo.
+const CacheFile = ".afto-cache.json"
+
+// cacheVersion is the version of the cache format. Caches of another version are discarded.
+const cacheVersion = 1
+
+// CacheEntry represents the cached Packages entry of a single deb.
+// An entry is valid while the deb at Path keeps its Size and ModTime, or its SHA256 hash.
+type CacheEntry struct {
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"mtime"`
+	SHA256  string `json:"sha256"`
+	Package string `json:"package"`
+	Stanza  string `json:"stanza"`
+}
+
+// Cache represents the package metadata cache of a repo, so unchanged debs are not read again.
+type Cache struct {
+	Version int                    `json:"version"`
+	Entries map[string]*CacheEntry `json:"entries"`
+
+	// Hits and Misses count the debs found in and missing from the cache since it was loaded.
+	Hits   int `json:"-"`
+	Misses int `json:"-"`
+
+	// used are the entries looked up since the cache was loaded, the others are dropped on save.
+	used map[string]bool
+	// read are the entries read from their deb since the cache was loaded, rather than found in the cache file.
+	read map[string]bool
+}
+
+// NewCache creates a new empty Cache.
+func NewCache() *Cache {
+	return &Cache{Version: cacheVersion, Entries: map[string]*CacheEntry{}, used: map[string]bool{}, read: map[string]bool{}}
+}
+
+// LoadCache reads the cache file at path. A missing, corrupt or outdated cache gives an empty cache,
+// as every entry can be read again from the debs.
+func LoadCache(path string) *Cache {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return NewCache()
+	}
+	c := NewCache()
+	if err := json.Unmarshal(data, c); err != nil || c.Version != cacheVersion || c.Entries == nil {
+		return NewCache()
+	}
+	return c
+}
+
+// Save writes the entries looked up since the cache was loaded to the cache file at path.
+func (c *Cache) Save(path string) error {
+	for p := range c.Entries {
+		if !c.used[p] {
+			delete(c.Entries, p)
+		}
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// lookup returns the cached Packages entry of the deb at path, or nil when the deb has changed.
+// The deb is only hashed when its modification time changed but its size did not.
+func (c *Cache) lookup(key string, path string, info os.FileInfo) *deb.Paragraph {
+	e, ok := c.Entries[key]
+	if !ok || e.Size != info.Size() {
+		return nil
+	}
+	if e.ModTime != info.ModTime().UnixNano() {
+		sum, err := sha256File(path)
+		if err != nil || sum != e.SHA256 {
+			return nil
+		}
+		e.ModTime = info.ModTime().UnixNano()
+	}
+	para, err := deb.ParseParagraph(e.Stanza)
+	if err != nil {
+		return nil
+	}
+	c.used[key] = true
+	return para
+}
+
+// keep keeps the entry of key in the cache when it is saved, although it was not looked up.
+func (c *Cache) keep(key string) {
+	if _, ok := c.Entries[key]; ok {
+		c.used[key] = true
+	}
+}
+
+// put caches the Packages entry of the deb at key.
+func (c *Cache) put(key string, info os.FileInfo, entry scanEntry) {
+	c.Entries[key] = &CacheEntry{
+		Path:    key,
+		Size:    info.Size(),
+		ModTime: info.ModTime().UnixNano(),
+		SHA256:  entry.para.Get("SHA256"),
+		Package: entry.pkg,
+		Stanza:  entry.para.String(),
+	}
+	c.used[key] = true
+	c.read[key] = true
+}
+
+// scanDebCached is like scanDeb but returns the cached entry of the deb at path when it is unchanged.
+// name is the path of the deb in the repo, used as its Filename and cache key.
+func scanDebCached(path string, name string, cache *Cache) (scanEntry, error) {
+	filename := debFilename(name)
+	if cache == nil {
+		return scanDeb(path, filename)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return scanEntry{}, err
+	}
+	if para := cache.lookup(name, path, info); para != nil {
+		cache.Hits++
+		para.Set("Filename", filename)
+		return scanEntry{pkg: para.Get("Package"), para: para}, nil
+	}
+
+	cache.Misses++
+	entry, err := scanDeb(path, filename)
+	if err != nil {
+		return scanEntry{}, err
+	}
+	cache.put(name, info, entry)
+	return entry, nil
+}
+
+// sha256File returns the hex encoded SHA256 hash of the file at path.
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
@@ -0,0 +1,88 @@
+package afutil
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hako/afto/deb"
+)
+
+// Kinds of changes between two Packages files.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// PackageChange represents a package which was added, removed or changed between two Packages files.
+// A package is changed when its versions or the debs of its versions differ.
+type PackageChange struct {
+	Kind       string
+	Package    string
+	OldVersion string
+	NewVersion string
+}
+
+// String returns the change in a single line. (changed: com.example.tweakexample 1.0 -> 1.1)
+func (c PackageChange) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return c.Kind + ": " + c.Package + " " + c.NewVersion
+	case ChangeRemoved:
+		return c.Kind + ": " + c.Package + " " + c.OldVersion
+	}
+	return c.Kind + ": " + c.Package + " " + c.OldVersion + " -> " + c.NewVersion
+}
+
+// DiffPackages compares the entries of two Packages files and returns the packages added, removed
+// and changed, sorted by package.
+func DiffPackages(old []*deb.Packages, new []*deb.Packages) []PackageChange {
+	before, after := packageVersions(old), packageVersions(new)
+	var changes []PackageChange
+	for pkg, v := range after {
+		prev, ok := before[pkg]
+		switch {
+		case !ok:
+			changes = append(changes, PackageChange{Kind: ChangeAdded, Package: pkg, NewVersion: v.versions})
+		case prev.versions != v.versions || prev.hashes != v.hashes:
+			changes = append(changes, PackageChange{Kind: ChangeChanged, Package: pkg, OldVersion: prev.versions, NewVersion: v.versions})
+		}
+	}
+	for pkg, v := range before {
+		if _, ok := after[pkg]; !ok {
+			changes = append(changes, PackageChange{Kind: ChangeRemoved, Package: pkg, OldVersion: v.versions})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Package < changes[j].Package
+	})
+	return changes
+}
+
+// versionSet holds the versions of a package in a Packages file and the hashes of their debs.
+type versionSet struct {
+	versions string
+	hashes   string
+}
+
+// packageVersions groups the entries of a Packages file by package.
+func packageVersions(entries []*deb.Packages) map[string]versionSet {
+	grouped := map[string][]*deb.Packages{}
+	for _, p := range entries {
+		grouped[p.Package()] = append(grouped[p.Package()], p)
+	}
+	sets := map[string]versionSet{}
+	for pkg, ps := range grouped {
+		sort.Slice(ps, func(i, j int) bool {
+			c, _ := deb.CompareVersions(ps[i].Version(), ps[j].Version())
+			return c < 0
+		})
+		var versions, hashes []string
+		for _, p := range ps {
+			versions = append(versions, p.Version())
+			hashes = append(hashes, p.SHA256()+p.MD5Sum())
+		}
+		sets[pkg] = versionSet{versions: strings.Join(versions, ", "), hashes: strings.Join(hashes, " ")}
+	}
+	return sets
+}
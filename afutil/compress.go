@@ -0,0 +1,99 @@
+package afutil
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/dsnet/compress/bzip2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// Compressions are the Packages compressions afto knows how to write, by file extension.
+var Compressions = []string{"bz2", "gz", "xz", "zst"}
+
+// DefaultCompressions are the Packages compressions generated when a repo does not choose any.
+// Cydia needs Packages.bz2, while Sileo and Zebra prefer Packages.xz and Packages.zst.
+var DefaultCompressions = []string{"bz2", "gz", "xz", "zst"}
+
+// ParseCompressions parses a comma separated list of compressions. ("bz2,xz")
+func ParseCompressions(list string) ([]string, error) {
+	var comps []string
+	for _, c := range strings.Split(list, ",") {
+		c = strings.TrimPrefix(strings.TrimSpace(c), ".")
+		if c == "" {
+			continue
+		}
+		if !IsCompression(c) {
+			return nil, errors.New("unsupported compression \"" + c + "\". (supported: " + strings.Join(Compressions, ", ") + ")")
+		}
+		comps = append(comps, c)
+	}
+	if len(comps) == 0 {
+		return nil, errors.New("no compressions given")
+	}
+	return comps, nil
+}
+
+// IsCompression returns whether afto can write the compression ext.
+func IsCompression(ext string) bool {
+	for _, c := range Compressions {
+		if c == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// Compress compresses data with the compression ext. (bz2, gz, xz or zst)
+func Compress(data []byte, ext string) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	var err error
+	switch ext {
+	case "bz2":
+		w, err = bzip2.NewWriter(&buf, &bzip2.WriterConfig{Level: bzip2.BestCompression})
+	case "gz":
+		w, err = gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	case "xz":
+		w, err = xz.NewWriter(&buf)
+	case "zst":
+		w, err = zstd.NewWriter(&buf, zstd.WithEncoderLevel(zstd.SpeedBestCompression))
+	default:
+		err = errors.New("unsupported compression \"" + ext + "\"")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// CompressPackages compresses the 'Packages' file in dir into Packages.<ext> for every compression in comps.
+func CompressPackages(dir string, comps []string) error {
+	packages, err := ioutil.ReadFile(filepath.Join(dir, "Packages"))
+	if err != nil {
+		return err
+	}
+	for _, c := range comps {
+		data, err := Compress(packages, c)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, "Packages."+c), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
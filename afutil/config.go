@@ -0,0 +1,335 @@
+package afutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile is the name of the afto config file looked up in a repo or the current directory.
+const ConfigFile = "afto.yaml"
+
+// Repo layouts.
+const (
+	LayoutFlat = "flat" // every deb in the repo root, next to Packages. (Cydia)
+	LayoutPool = "pool" // debs in pool/<component>/<letter>/<package>/ and indexes in dists/<suite>/ too. (APT)
+)
+
+// Config represents the afto config file of a repo. (afto.yaml)
+// Keys which are not set keep the default value.
+type Config struct {
+	Origin         string
+	Label          string
+	Suite          string
+	Suites         []string
+	Codename       string
+	Architectures  []string
+	Components     []string
+	Description    string
+	Icon           string
+	Icon2x         string
+	Icon3x         string
+	Compressions   []string
+	SigningKey     string
+	PassphraseFile string
+	Port           string
+	ValidFor       time.Duration
+	NotAutomatic   bool
+	AcquireByHash  bool
+	Layout         string
+	KeepVersions   int
+	KeepFor        time.Duration
+
+	// path is the file the config was loaded from, empty for the default config.
+	path string
+}
+
+// ConfigError represents an invalid key in a config file. Line is the line of the key, 0 when unknown.
+type ConfigError struct {
+	File string
+	Line int
+	Key  string
+	Msg  string
+}
+
+// Error returns the config error with the file, line and offending key. (afto.yaml:3: key "port": must be a number)
+func (e *ConfigError) Error() string {
+	file := e.File
+	if e.Line > 0 {
+		file += ":" + strconv.Itoa(e.Line)
+	}
+	if e.Key == "" {
+		return file + ": " + e.Msg
+	}
+	return file + ": key \"" + e.Key + "\": " + e.Msg
+}
+
+// DefaultConfig returns the config used when there is no config file.
+func DefaultConfig() *Config {
+	return &Config{
+		Origin:        "afto beta repo",
+		Label:         "apt.afto.repo",
+		Suite:         "beta",
+		Codename:      "afto",
+		Architectures: []string{"iphoneos-arm"},
+		Components:    []string{"main"},
+		Description:   "A default repo generated by afto",
+		Compressions:  DefaultCompressions,
+		Port:          "2468",
+		Layout:        LayoutFlat,
+		KeepVersions:  1,
+	}
+}
+
+// LoadConfig reads and validates the config file at path.
+// The default config is returned when path does not exist.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return DefaultConfig(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(path, data)
+}
+
+// ParseConfig parses and validates the config file data. path is used in errors and to resolve icon paths.
+func ParseConfig(path string, data []byte) (*Config, error) {
+	c := DefaultConfig()
+	c.path = path
+
+	// Decode into nodes rather than a map, to keep the order and the line of every key.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, &ConfigError{File: path, Msg: strings.TrimPrefix(err.Error(), "yaml: ")}
+	}
+	var items []*yaml.Node
+	if len(doc.Content) > 0 {
+		root := doc.Content[0]
+		if root.Kind != yaml.MappingNode {
+			return nil, &ConfigError{File: path, Line: root.Line, Msg: "must be a mapping of keys to values"}
+		}
+		items = root.Content
+	}
+
+	var validFor, keepFor string
+	keys := map[string]interface{}{
+		"origin":          &c.Origin,
+		"label":           &c.Label,
+		"suite":           &c.Suite,
+		"suites":          &c.Suites,
+		"codename":        &c.Codename,
+		"architectures":   &c.Architectures,
+		"components":      &c.Components,
+		"description":     &c.Description,
+		"icon":            &c.Icon,
+		"icon@2x":         &c.Icon2x,
+		"icon@3x":         &c.Icon3x,
+		"compressions":    &c.Compressions,
+		"signing_key":     &c.SigningKey,
+		"passphrase_file": &c.PassphraseFile,
+		"port":            &c.Port,
+		"valid_for":       &validFor,
+		"not_automatic":   &c.NotAutomatic,
+		"acquire_by_hash": &c.AcquireByHash,
+		"layout":          &c.Layout,
+		"keep_versions":   &c.KeepVersions,
+		"keep_for":        &keepFor,
+	}
+	lines := map[string]int{}
+	for i := 0; i+1 < len(items); i += 2 {
+		// Keys which are not strings (1, true...) are named as written.
+		key, value := items[i].Value, items[i+1]
+		line := items[i].Line
+		ptr, ok := keys[key]
+		if !ok {
+			return nil, &ConfigError{File: path, Line: line, Key: key, Msg: "unknown key"}
+		}
+		if lines[key] > 0 {
+			return nil, &ConfigError{File: path, Line: line, Key: key, Msg: "defined more than once"}
+		}
+		lines[key] = line
+
+		// Decode each value on its own so type errors name the key.
+		if list, ok := ptr.(*[]string); ok {
+			// Lists can also be written as a comma separated string. (bz2, xz)
+			var one string
+			if value.Kind == yaml.ScalarNode && value.Decode(&one) == nil {
+				*list = strings.FieldsFunc(one, func(r rune) bool { return r == ',' || r == ' ' })
+				continue
+			}
+			if err := value.Decode(list); err != nil {
+				return nil, &ConfigError{File: path, Line: line, Key: key, Msg: "must be a list of strings"}
+			}
+			continue
+		}
+		if err := value.Decode(ptr); err != nil {
+			kind := "a string"
+			switch ptr.(type) {
+			case *bool:
+				kind = "true or false"
+			case *int:
+				kind = "a number"
+			}
+			return nil, &ConfigError{File: path, Line: line, Key: key, Msg: "must be " + kind}
+		}
+	}
+
+	if validFor != "" {
+		d, err := parseDuration(validFor)
+		if err != nil || d <= 0 {
+			return nil, &ConfigError{File: path, Line: lines["valid_for"], Key: "valid_for", Msg: "\"" + validFor + "\" is not a valid duration. (7d, 12h...)"}
+		}
+		c.ValidFor = d
+	}
+
+	if keepFor != "" {
+		d, err := parseDuration(keepFor)
+		if err != nil || d <= 0 {
+			return nil, &ConfigError{File: path, Line: lines["keep_for"], Key: "keep_for", Msg: "\"" + keepFor + "\" is not a valid duration. (30d, 12h...)"}
+		}
+		c.KeepFor = d
+	}
+
+	// The first suite is the default one, unless another is set.
+	if len(c.Suites) > 0 && lines["suite"] == 0 {
+		c.Suite = c.Suites[0]
+	}
+
+	if err := c.validate(); err != nil {
+		if cerr, ok := err.(*ConfigError); ok {
+			cerr.Line = lines[cerr.Key]
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// ForSuite returns a copy of the config for the Release files of suite.
+func (c *Config) ForSuite(suite string) *Config {
+	cfg := *c
+	cfg.Suite = suite
+	cfg.Suites = nil
+	return &cfg
+}
+
+// HasSuite returns whether suite is one of the suites of the config.
+func (c *Config) HasSuite(suite string) bool {
+	return contains(c.Suites, suite)
+}
+
+// validate checks the values of the config.
+func (c *Config) validate() error {
+	for _, f := range []struct{ key, value string }{{"origin", c.Origin}, {"label", c.Label}, {"description", c.Description}} {
+		if strings.ContainsAny(f.value, "\n\r") {
+			return c.errorf(f.key, "must be a single line")
+		}
+	}
+	for _, f := range []struct {
+		key    string
+		values []string
+	}{{"suite", []string{c.Suite}}, {"codename", []string{c.Codename}}, {"architectures", c.Architectures}, {"components", c.Components}} {
+		if len(f.values) == 0 || f.values[0] == "" {
+			return c.errorf(f.key, "must not be empty")
+		}
+		for _, v := range f.values {
+			if !isName(v) {
+				return c.errorf(f.key, "\""+v+"\" may only contain letters, digits, '.', '_' and '-'")
+			}
+		}
+	}
+
+	for i, suite := range c.Suites {
+		if !isName(suite) {
+			return c.errorf("suites", "\""+suite+"\" may only contain letters, digits, '.', '_' and '-'")
+		}
+		if contains(c.Suites[:i], suite) {
+			return c.errorf("suites", "\""+suite+"\" is listed more than once")
+		}
+	}
+	if len(c.Suites) > 0 && !c.HasSuite(c.Suite) {
+		return c.errorf("suite", "\""+c.Suite+"\" is not one of the suites. ("+strings.Join(c.Suites, ", ")+")")
+	}
+
+	comps, err := ParseCompressions(strings.Join(c.Compressions, ","))
+	if err != nil {
+		return c.errorf("compressions", err.Error())
+	}
+	c.Compressions = comps
+
+	if c.Layout != LayoutFlat && c.Layout != LayoutPool {
+		return c.errorf("layout", "\""+c.Layout+"\" is not a layout. (flat or pool)")
+	}
+	// Suites share the pool, each listing its debs under dists/.
+	if c.Layout == LayoutFlat && len(c.Suites) > 0 {
+		return c.errorf("suites", "suites need layout: pool")
+	}
+	// A flat repo has a single Packages file, with no index for each component and architecture.
+	if c.Layout == LayoutFlat && len(c.Components) > 1 {
+		return c.errorf("components", "more than one component needs layout: pool")
+	}
+	if c.Layout == LayoutFlat && len(c.Architectures) > 1 {
+		return c.errorf("architectures", "more than one architecture needs layout: pool")
+	}
+
+	if c.KeepVersions < 1 {
+		return c.errorf("keep_versions", "must be at least 1")
+	}
+
+	if n, err := strconv.Atoi(c.Port); err != nil || n < 1 || n > 65535 {
+		return c.errorf("port", "\""+c.Port+"\" is not a valid port number")
+	}
+
+	for _, f := range []struct {
+		key  string
+		path *string
+	}{{"icon", &c.Icon}, {"icon@2x", &c.Icon2x}, {"icon@3x", &c.Icon3x}, {"signing_key", &c.SigningKey}, {"passphrase_file", &c.PassphraseFile}} {
+		if *f.path == "" {
+			continue
+		}
+		// Paths are relative to the config file.
+		if !filepath.IsAbs(*f.path) {
+			*f.path = filepath.Join(filepath.Dir(c.path), *f.path)
+		}
+		if _, err := os.Stat(*f.path); err != nil {
+			return c.errorf(f.key, "file \""+*f.path+"\" does not exist")
+		}
+	}
+	return nil
+}
+
+// parseDuration is like time.ParseDuration but also accepts a number of days. (7d)
+func parseDuration(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// errorf returns a ConfigError for key.
+func (c *Config) errorf(key string, msg string) error {
+	return &ConfigError{File: c.path, Key: key, Msg: msg}
+}
+
+// isName returns whether s is a valid suite, codename, component or architecture name. (iphoneos-arm64)
+func isName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,177 @@
+package afutil
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/hako/afto/deb"
+)
+
+// dependsFields are the relationship fields which must be satisfiable for a package to install.
+var dependsFields = []string{"Pre-Depends", "Depends"}
+
+// DependencyProblem represents a dependency of a package in a repo which cannot be satisfied.
+type DependencyProblem struct {
+	Package    string
+	Version    string
+	Field      string
+	Dependency deb.Alternatives
+}
+
+// String returns the problem in a readable form. (com.example.tweak 1.0: Depends "foo (>= 2.0)" cannot be satisfied)
+func (p DependencyProblem) String() string {
+	return p.Package + " " + p.Version + ": " + p.Field + " \"" + p.Dependency.String() + "\" cannot be satisfied"
+}
+
+// CheckDependencies reports the Pre-Depends and Depends of the given packages which cannot be satisfied
+// by the packages themselves. The packages are Packages entries, as the cache of a repo holds them.
+// (Builder.Entries) Dependencies on packages which are neither in nor provided by the repo are assumed
+// to come from another repo (mobilesubstrate, firmware...) and are not reported.
+// A dependency is only satisfied by packages of the architecture of the package, or of "all".
+func CheckDependencies(controls []*deb.Packages) ([]DependencyProblem, error) {
+	available, err := availablePackages(controls)
+	if err != nil {
+		return nil, err
+	}
+	return dependencyProblems(controls, func(arch string, alts deb.Alternatives) bool {
+		return satisfiable(alts, arch, available)
+	})
+}
+
+// CheckRemoval reports the Pre-Depends and Depends of the packages left in a repo which can no longer
+// be satisfied once the packages removed are taken out of it. Unlike CheckDependencies, dependencies
+// which only the removed packages satisfied are reported, and the problems the repo already had are not.
+func CheckRemoval(left []*deb.Packages, removed []*deb.Packages) ([]DependencyProblem, error) {
+	before, err := availablePackages(append(append([]*deb.Packages{}, left...), removed...))
+	if err != nil {
+		return nil, err
+	}
+	after, err := availablePackages(left)
+	if err != nil {
+		return nil, err
+	}
+	// The removed packages are still known to the repo, without any version to satisfy a dependency.
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			after[name] = nil
+		}
+	}
+	return dependencyProblems(left, func(arch string, alts deb.Alternatives) bool {
+		return !satisfiable(alts, arch, before) || satisfiable(alts, arch, after)
+	})
+}
+
+// availableVersion represents a version of a real or virtual package of a repo, and its architecture.
+type availableVersion struct {
+	version string
+	arch    string
+}
+
+// availablePackages returns every real and virtual package of controls, with the versions available.
+// A virtual package has the architecture of the package providing it.
+func availablePackages(controls []*deb.Packages) (map[string][]availableVersion, error) {
+	available := map[string][]availableVersion{}
+	for _, c := range controls {
+		available[c.Package()] = append(available[c.Package()], availableVersion{c.Version(), c.Arch()})
+		provides, err := c.Relations("Provides")
+		if err != nil {
+			return nil, errors.New(c.Package() + ": " + err.Error())
+		}
+		for _, alts := range provides {
+			for _, r := range alts {
+				version := ""
+				if r.Constraint != nil && r.Constraint.Op == "=" {
+					version = r.Constraint.Version.String()
+				}
+				available[r.Name] = append(available[r.Name], availableVersion{version, c.Arch()})
+			}
+		}
+	}
+	return available, nil
+}
+
+// dependencyProblems reports the Pre-Depends and Depends of controls which ok does not accept
+// for the architecture of the package, sorted by package.
+func dependencyProblems(controls []*deb.Packages, ok func(arch string, alts deb.Alternatives) bool) ([]DependencyProblem, error) {
+	var problems []DependencyProblem
+	for _, c := range controls {
+		for _, field := range dependsFields {
+			rel, err := c.Relations(field)
+			if err != nil {
+				return nil, errors.New(c.Package() + ": " + err.Error())
+			}
+			for _, alts := range rel {
+				if !ok(c.Arch(), alts) {
+					problems = append(problems, DependencyProblem{
+						Package:    c.Package(),
+						Version:    c.Version(),
+						Field:      field,
+						Dependency: alts,
+					})
+				}
+			}
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Package < problems[j].Package
+	})
+	return problems, nil
+}
+
+// satisfiable returns whether any of the alternatives is satisfied, for a package of arch, by the available
+// packages or refers to a package outside of the repo. Alternatives whose architecture list ([iphoneos-arm64])
+// excludes arch do not apply, and a dependency none of them applies to is satisfied.
+func satisfiable(alts deb.Alternatives, arch string, available map[string][]availableVersion) bool {
+	applied := false
+	for _, r := range alts {
+		if !archListed(r.Archs, arch) {
+			continue
+		}
+		applied = true
+		versions, ok := available[r.Name]
+		if !ok {
+			return true
+		}
+		for _, v := range versions {
+			if archSatisfies(r.Arch, arch, v.arch) && r.Match(r.Name, v.version) {
+				return true
+			}
+		}
+	}
+	return !applied
+}
+
+// archListed returns whether the architecture list of a relation includes arch. (iphoneos-arm64 !iphoneos-arm)
+// An empty list, or a package of "all", includes every architecture.
+func archListed(archs []string, arch string) bool {
+	if len(archs) == 0 || arch == "all" {
+		return true
+	}
+	negated := false
+	for _, a := range archs {
+		if strings.HasPrefix(a, "!") {
+			negated = true
+			if a[1:] == arch {
+				return false
+			}
+		} else if a == arch {
+			return true
+		}
+	}
+	return negated
+}
+
+// archSatisfies returns whether a package of candidate satisfies the relation of a package of arch,
+// qualified by qualifier. (foo:any, foo:iphoneos-arm64) Packages of "all" satisfy every architecture,
+// and packages of "all" can depend on every architecture.
+func archSatisfies(qualifier string, arch string, candidate string) bool {
+	switch qualifier {
+	case "any":
+		return true
+	case "", "native":
+	default:
+		arch = qualifier
+	}
+	return arch == "all" || candidate == "all" || candidate == arch
+}
@@ -0,0 +1,68 @@
+package afutil
+
+import (
+	"path"
+	"strings"
+
+	"github.com/hako/afto/deb"
+)
+
+// PackageSummary represents a package of a repo in a single line, as afto list prints it.
+type PackageSummary struct {
+	Package      string `json:"package"`
+	Name         string `json:"name"`
+	Version      string `json:"version"`
+	Architecture string `json:"architecture"`
+	Section      string `json:"section"`
+	Size         int    `json:"size"`
+}
+
+// Summarize returns the summary of the package p.
+func Summarize(p deb.PackagesInterface) PackageSummary {
+	return PackageSummary{
+		Package:      p.Package(),
+		Name:         p.Name(),
+		Version:      p.Version(),
+		Architecture: p.Arch(),
+		Section:      p.Section(),
+		Size:         p.Size(),
+	}
+}
+
+// PackageFilter selects packages of a repo. Empty fields select every package.
+type PackageFilter struct {
+	Pattern      string // glob or substring of the package or its name. (com.example.*)
+	Architecture string
+	Section      string
+}
+
+// Match returns whether the filter selects the package p. Sections are matched ignoring case.
+func (f PackageFilter) Match(p deb.DpkgInterface) bool {
+	if f.Architecture != "" && p.Arch() != f.Architecture {
+		return false
+	}
+	if f.Section != "" && !strings.EqualFold(p.Section(), f.Section) {
+		return false
+	}
+	if f.Pattern == "" {
+		return true
+	}
+	for _, s := range []string{p.Package(), p.Name()} {
+		if ok, _ := path.Match(f.Pattern, s); ok || strings.Contains(strings.ToLower(s), strings.ToLower(f.Pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fields returns the fields of the package p by name, for printing it as JSON.
+func Fields(p deb.DpkgInterface) map[string]string {
+	fields := map[string]string{}
+	if p.Paragraph() == nil {
+		return fields
+	}
+	for _, f := range p.Paragraph().Fields() {
+		fields[f.Name] = f.Value
+	}
+	return fields
+}
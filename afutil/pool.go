@@ -0,0 +1,124 @@
+package afutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hako/afto/deb"
+)
+
+// PoolPath returns the path of a deb of the package pkg in a repo with the pool layout.
+// Packages are grouped by their first letter, or their first four letters for libraries.
+// (pool/main/c/com.example.tweakexample/com.example.tweakexample_1.0_iphoneos-arm.deb)
+func PoolPath(component string, pkg string, file string) string {
+	pkg = strings.ToLower(pkg)
+	prefix := pkg[:1]
+	if strings.HasPrefix(pkg, "lib") && len(pkg) > 3 {
+		prefix = pkg[:4]
+	}
+	return path.Join("pool", component, prefix, pkg, file)
+}
+
+// DistsDir returns the directory of the Release file of suite in a repo with the pool layout. (dists/beta)
+func DistsDir(suite string) string {
+	return path.Join("dists", suite)
+}
+
+// BinaryDir returns the directory of the Packages files of a component and architecture,
+// relative to the dists directory of a suite. (main/binary-iphoneos-arm)
+func BinaryDir(component string, arch string) string {
+	return path.Join(component, "binary-"+arch)
+}
+
+// LoadSuitePackages reads every entry of the Packages files of suite in a repo with the pool layout.
+// (dists/beta/main/binary-iphoneos-arm/Packages...) A deb listed for several architectures is returned once.
+// A suite which was never generated has no entries.
+func LoadSuitePackages(repo string, suite string) ([]*deb.Packages, error) {
+	dists := filepath.Join(repo, filepath.FromSlash(DistsDir(suite)))
+	components, err := ioutil.ReadDir(dists)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []*deb.Packages
+	seen := map[string]bool{}
+	for _, c := range components {
+		if !c.IsDir() || c.Name() == "by-hash" {
+			continue
+		}
+		archs, err := ioutil.ReadDir(filepath.Join(dists, c.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range archs {
+			if !a.IsDir() || !strings.HasPrefix(a.Name(), "binary-") {
+				continue
+			}
+			// The indexes of an architecture no longer in the suite are removed, but not their directory.
+			index, err := LoadPackages(filepath.Join(dists, c.Name(), a.Name()))
+			if err != nil && hasPackages(filepath.Join(dists, c.Name(), a.Name())) {
+				return nil, err
+			}
+			for _, e := range index {
+				if name := debName(e); !seen[name] {
+					seen[name] = true
+					entries = append(entries, e)
+				}
+			}
+		}
+	}
+	return entries, nil
+}
+
+// RepoPackages reads every entry of the Packages files of a repo, the root one and the ones of
+// every suite under dists/, so the debs of all the suites are listed. Every deb is returned once.
+func RepoPackages(repo string) ([]*deb.Packages, error) {
+	entries, err := LoadPackages(repo)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for _, e := range entries {
+		seen[debName(e)] = true
+	}
+	suites, err := ioutil.ReadDir(filepath.Join(repo, "dists"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, s := range suites {
+		if !s.IsDir() {
+			continue
+		}
+		suiteEntries, err := LoadSuitePackages(repo, s.Name())
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range suiteEntries {
+			if name := debName(e); !seen[name] {
+				seen[name] = true
+				entries = append(entries, e)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// debName returns the path of the deb of a Packages entry in the repo. (foo.deb for ./foo.deb)
+func debName(e *deb.Packages) string {
+	return path.Clean(e.Filename())
+}
+
+// hasPackages returns whether the directory dir holds a Packages file or one of its compressed variants.
+func hasPackages(dir string) bool {
+	for _, name := range append([]string{"Packages"}, PackagesFiles(Compressions)...) {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package afutil
+
+import (
+	"os"
+	"sort"
+	"time"
+
+	"github.com/hako/afto/deb"
+)
+
+// ExpiredDebs returns the debs the retention policy of cfg removes from a repo, sorted by name.
+// A deb expires when it is not one of the newest cfg.KeepVersions versions of its package for its architecture,
+// and, when cfg.KeepFor is set, it was last modified more than cfg.KeepFor before now.
+// The newest version always stays. Debs for all architectures are kept apart from the others.
+// Only the debs of pkg are considered, unless pkg is empty.
+// The packages and versions of the debs are the ones of their Packages entries. (Builder.Entries)
+func ExpiredDebs(debs []DebEntry, cfg *Config, pkg string, now time.Time) ([]DebFile, error) {
+	type version struct {
+		deb     DebFile
+		version string
+	}
+	grouped := map[string][]version{}
+	for _, d := range debs {
+		c := d.Entry
+		if pkg != "" && c.Package() != pkg {
+			continue
+		}
+		// Every architecture keeps its own versions, and so do the debs for all of them.
+		key := c.Package() + " " + c.Arch()
+		grouped[key] = append(grouped[key], version{deb: d.DebFile, version: c.Version()})
+	}
+
+	var expired []DebFile
+	for _, versions := range grouped {
+		// Newest first.
+		sort.SliceStable(versions, func(i, j int) bool {
+			c, _ := deb.CompareVersions(versions[i].version, versions[j].version)
+			return c > 0
+		})
+		keep := cfg.KeepVersions
+		if keep < 1 {
+			keep = 1
+		}
+		for i := keep; i < len(versions); i++ {
+			if cfg.KeepFor > 0 {
+				info, err := os.Stat(versions[i].deb.Path)
+				if err != nil {
+					return nil, err
+				}
+				if now.Sub(info.ModTime()) < cfg.KeepFor {
+					continue
+				}
+			}
+			expired = append(expired, versions[i].deb)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].Name < expired[j].Name
+	})
+	return expired, nil
+}
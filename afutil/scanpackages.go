@@ -0,0 +1,214 @@
+package afutil
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hako/afto/deb"
+)
+
+// scanFieldOrder is the order dpkg-scanpackages writes the known fields of a Packages stanza in.
+// Fields not listed here are written afterwards in byte order.
+var scanFieldOrder = []string{
+	"Package", "Package-Type", "Source", "Version", "Kernel-Version", "Built-For-Profiles",
+	"Auto-Built-Package", "Architecture", "Subarchitecture", "Installer-Menu-Item",
+	"Build-Essential", "Essential", "Protected", "Origin", "Bugs", "Maintainer",
+	"Installed-Size", "Pre-Depends", "Depends", "Recommends", "Suggests", "Enhances",
+	"Conflicts", "Breaks", "Replaces", "Provides", "Built-Using", "Static-Built-Using",
+	"Filename", "Size", "MD5sum", "SHA1", "SHA256", "Section", "Priority", "Multi-Arch",
+	"Homepage", "Description", "Tag", "Task",
+}
+
+// scanEntry is a single package entry of a Packages file.
+type scanEntry struct {
+	pkg  string
+	para *deb.Paragraph
+}
+
+// ScanPackages generates the contents of a Packages file for the debs found in dir.
+// debs are paths relative to dir and the output matches `dpkg-scanpackages -m . /dev/null`.
+func ScanPackages(dir string, debs []string) ([]byte, error) {
+	return ScanPackagesCached(dir, debs, nil)
+}
+
+// ScanPackagesCached is like ScanPackages but only reads the debs which are not in cache or have changed.
+// The cache is updated with the debs read. A nil cache reads every deb.
+func ScanPackagesCached(dir string, debs []string, cache *Cache) ([]byte, error) {
+	var files []DebFile
+	for _, d := range debs {
+		files = append(files, DebFile{Path: filepath.Join(dir, d), Name: filepath.ToSlash(d)})
+	}
+	return ScanDebFiles(files, cache)
+}
+
+// DebFile represents a deb file on disk and its path in the repo. (Packages Filename)
+type DebFile struct {
+	Path string
+	Name string
+}
+
+// DebError is returned when the deb name of a repo cannot be read.
+type DebError struct {
+	Name string
+	Err  error
+}
+
+// Error returns the deb and why it cannot be read.
+func (e *DebError) Error() string {
+	return e.Name + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error, such as the *os.PathError of the deb.
+func (e *DebError) Unwrap() error {
+	return e.Err
+}
+
+// ScanDebFiles generates the contents of a Packages file for the debs in files, which do not
+// have to be in the repo yet. Debs in cache are only read when they have changed.
+func ScanDebFiles(files []DebFile, cache *Cache) ([]byte, error) {
+	entries, err := scanDebFiles(files, cache)
+	if err != nil {
+		return nil, err
+	}
+	return formatEntries(entries), nil
+}
+
+// scanDebFiles returns the Packages entries of the debs in files, sorted by name and then by filename.
+func scanDebFiles(files []DebFile, cache *Cache) ([]scanEntry, error) {
+	var entries []scanEntry
+	for _, f := range files {
+		entry, err := scanDebCached(f.Path, f.Name, cache)
+		if err != nil {
+			return nil, &DebError{Name: f.Name, Err: err}
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].pkg != entries[j].pkg {
+			return entries[i].pkg < entries[j].pkg
+		}
+		return entries[i].para.Get("Filename") < entries[j].para.Get("Filename")
+	})
+	return entries, nil
+}
+
+// formatEntries returns the contents of a Packages file holding entries.
+func formatEntries(entries []scanEntry) []byte {
+	var buf bytes.Buffer
+	for _, e := range entries {
+		e.para.WriteTo(&buf)
+		buf.WriteString("\n")
+	}
+	return buf.Bytes()
+}
+
+// debFilename returns the Packages Filename of the deb name in the repo.
+// Debs in the repo root are written the way dpkg-scanpackages does. (./foo.deb, pool/main/f/foo/foo.deb)
+func debFilename(name string) string {
+	if strings.Contains(name, "/") {
+		return name
+	}
+	return "./" + name
+}
+
+// scanDeb reads the control stanza of the deb at path and adds the Filename, Size and checksum fields.
+func scanDeb(path string, filename string) (scanEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return scanEntry{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return scanEntry{}, err
+	}
+	if info.Size() == 0 {
+		return scanEntry{}, errors.New("deb file is empty")
+	}
+
+	control, err := deb.ReadControlFile(f)
+	if err != nil {
+		return scanEntry{}, err
+	}
+	para, err := deb.ParseParagraph(string(control))
+	if err != nil {
+		return scanEntry{}, errors.New("control file " + err.Error())
+	}
+
+	md5h, sha1h, sha256h := md5.New(), sha1.New(), sha256.New()
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return scanEntry{}, err
+	}
+	if _, err := io.Copy(io.MultiWriter(md5h, sha1h, sha256h), f); err != nil {
+		return scanEntry{}, err
+	}
+
+	pkg := para.Get("Package")
+	if pkg == "" {
+		return scanEntry{}, errors.New("no Package field in control file")
+	}
+	para.Set("Filename", filename)
+	para.Set("Size", strconv.FormatInt(info.Size(), 10))
+	para.Set("MD5sum", fmt.Sprintf("%x", md5h.Sum(nil)))
+	para.Set("SHA1", fmt.Sprintf("%x", sha1h.Sum(nil)))
+	para.Set("SHA256", fmt.Sprintf("%x", sha256h.Sum(nil)))
+	return scanEntry{pkg: pkg, para: sortScanFields(para.Fields())}, nil
+}
+
+// sortScanFields returns a paragraph of fields named and ordered the way dpkg-scanpackages does.
+func sortScanFields(fields []deb.Field) *deb.Paragraph {
+	rank := make(map[string]int, len(scanFieldOrder))
+	for i, name := range scanFieldOrder {
+		rank[name] = i + 1
+	}
+	sorted := make([]deb.Field, len(fields))
+	for i, f := range fields {
+		sorted[i] = deb.Field{Name: capitalizeField(f.Name), Value: f.Value}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, rj := rank[sorted[i].Name], rank[sorted[j].Name]
+		switch {
+		case ri != 0 && rj != 0:
+			return ri < rj
+		case ri != 0:
+			return true
+		case rj != 0:
+			return false
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	para := deb.NewParagraph()
+	for _, f := range sorted {
+		para.Set(f.Name, f.Value)
+	}
+	return para
+}
+
+// capitalizeField returns the spelling dpkg uses for a field name. (sileodepiction -> Sileodepiction)
+func capitalizeField(name string) string {
+	for _, known := range scanFieldOrder {
+		if strings.EqualFold(known, name) {
+			return known
+		}
+	}
+	parts := strings.Split(strings.ToLower(name), "-")
+	for i, p := range parts {
+		if p != "" {
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		}
+	}
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,204 @@
+package afutil
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/clearsign"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+)
+
+// PassphraseEnv is the environment variable holding the passphrase of the signing key.
+const PassphraseEnv = "AFTO_PASSPHRASE"
+
+// signConfig makes signatures use SHA256, as APT rejects SHA1 signatures.
+var signConfig = &packet.Config{DefaultHash: crypto.SHA256}
+
+// ReadPassphrase returns the passphrase of the signing key from the AFTO_PASSPHRASE environment
+// variable, or else from the first line of file. It returns nil when neither is set.
+func ReadPassphrase(file string) ([]byte, error) {
+	if p, ok := os.LookupEnv(PassphraseEnv); ok {
+		return []byte(p), nil
+	}
+	if file == "" {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(strings.TrimRight(strings.SplitN(string(data), "\n", 2)[0], "\r")), nil
+}
+
+// LoadSigningKey loads the first secret key able to sign from an armored key file or a binary keyring,
+// decrypting it with passphrase when it is encrypted.
+func LoadSigningKey(path string, passphrase []byte) (*openpgp.Entity, error) {
+	keyring, err := ReadKeyRing(path)
+	if err != nil {
+		return nil, errors.New(path + ": " + err.Error())
+	}
+
+	for _, e := range keyring {
+		if e.PrivateKey == nil || !e.PrivateKey.CanSign() {
+			continue
+		}
+		if e.PrivateKey.Encrypted {
+			if passphrase == nil {
+				return nil, errors.New(path + ": key is encrypted, set " + PassphraseEnv + " or passphrase_file")
+			}
+			if err := e.PrivateKey.Decrypt(passphrase); err != nil {
+				return nil, errors.New(path + ": wrong passphrase")
+			}
+		}
+		return e, nil
+	}
+	return nil, errors.New(path + ": no secret key able to sign found")
+}
+
+// SignRelease signs the Release file of the repo dir with key, writing the detached signature
+// to Release.gpg and the clearsigned Release file to InRelease.
+// Both signatures are written to temporary files and verified before they replace the ones in dir.
+func SignRelease(dir string, key *openpgp.Entity) error {
+	release, err := ioutil.ReadFile(dir + "/Release")
+	if err != nil {
+		return err
+	}
+
+	var detached bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&detached, key, bytes.NewReader(release), signConfig); err != nil {
+		return err
+	}
+	var inRelease bytes.Buffer
+	w, err := clearsign.Encode(&inRelease, key.PrivateKey, signConfig)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(release); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	detachedFile, err := writeTemp(dir, "Release.gpg", detached.Bytes())
+	if err != nil {
+		return err
+	}
+	defer os.Remove(detachedFile)
+	inReleaseFile, err := writeTemp(dir, "InRelease", inRelease.Bytes())
+	if err != nil {
+		return err
+	}
+	defer os.Remove(inReleaseFile)
+
+	keyring := openpgp.EntityList{key}
+	if err := checkDetached(release, detachedFile, keyring); err != nil {
+		return err
+	}
+	if err := checkInRelease(release, inReleaseFile, keyring); err != nil {
+		return err
+	}
+	if err := os.Rename(detachedFile, dir+"/Release.gpg"); err != nil {
+		return err
+	}
+	return os.Rename(inReleaseFile, dir+"/InRelease")
+}
+
+// writeTemp writes data to a new temporary file in dir named after name, and returns its path.
+func writeTemp(dir string, name string, data []byte) (string, error) {
+	f, err := ioutil.TempFile(dir, "."+name+"-")
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// VerifyRelease checks the Release.gpg and InRelease signatures of the repo dir against keyring.
+func VerifyRelease(dir string, keyring openpgp.KeyRing) error {
+	if err := verifyDetached(dir, keyring); err != nil {
+		return err
+	}
+	return verifyInRelease(dir, keyring)
+}
+
+// verifyDetached checks the Release.gpg signature of the repo dir against keyring.
+func verifyDetached(dir string, keyring openpgp.KeyRing) error {
+	release, err := ioutil.ReadFile(dir + "/Release")
+	if err != nil {
+		return err
+	}
+	return checkDetached(release, dir+"/Release.gpg", keyring)
+}
+
+// verifyInRelease checks the InRelease signature of the repo dir against keyring,
+// and that the signed text is the Release file.
+func verifyInRelease(dir string, keyring openpgp.KeyRing) error {
+	release, err := ioutil.ReadFile(dir + "/Release")
+	if err != nil {
+		return err
+	}
+	return checkInRelease(release, dir+"/InRelease", keyring)
+}
+
+// checkDetached checks the detached signature in the file sig of the Release file contents release against keyring.
+func checkDetached(release []byte, sig string, keyring openpgp.KeyRing) error {
+	detached, err := ioutil.ReadFile(sig)
+	if err != nil {
+		return err
+	}
+	if _, err := openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(release), bytes.NewReader(detached), nil); err != nil {
+		return errors.New("Release.gpg: " + err.Error())
+	}
+	return nil
+}
+
+// checkInRelease checks the clearsigned file signed against keyring, and that the signed text is release.
+func checkInRelease(release []byte, signed string, keyring openpgp.KeyRing) error {
+	inRelease, err := ioutil.ReadFile(signed)
+	if err != nil {
+		return err
+	}
+	block, _ := clearsign.Decode(inRelease)
+	if block == nil {
+		return errors.New("InRelease: not a clearsigned file")
+	}
+	if _, err := openpgp.CheckDetachedSignature(keyring, bytes.NewReader(block.Bytes), block.ArmoredSignature.Body, nil); err != nil {
+		return errors.New("InRelease: " + err.Error())
+	}
+	if !bytes.Equal(block.Plaintext, release) {
+		return errors.New("InRelease: signed text differs from Release")
+	}
+	return nil
+}
+
+// ReadKeyRing reads an armored or binary keyring. (a public key to verify a repo, or a secret key to sign it)
+func ReadKeyRing(path string) (openpgp.EntityList, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, aerr := armor.Decode(bytes.NewReader(data)); aerr == nil {
+		return openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	}
+	return openpgp.ReadKeyRing(bytes.NewReader(data))
+}
@@ -0,0 +1,482 @@
+package afutil
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// StagingPrefix is the prefix of the staging directories created inside a repo.
+const StagingPrefix = ".afto-staging-"
+
+// backupDir is the directory inside a staging directory holding the replaced repo files.
+const backupDir = ".backup"
+
+// journalFile is the file inside a staging directory recording what was done to the repo,
+// so a staging directory left by a crash can be rolled back.
+const journalFile = ".journal"
+
+// Staging states recorded in the journal.
+const (
+	stagingPublishing = "publishing" // files are being swapped into the repo.
+	stagingDone       = "done"       // every file was published, the staging directory is only left to remove.
+)
+
+// staleAge is how old a staging directory without a journal must be to be removed,
+// as its journal is written right after it is created.
+const staleAge = time.Minute
+
+// Staging represents a staging directory where the files of a repo are written before they are
+// published. Publishing swaps every file into the repo with an atomic rename, and rolls back
+// the files already swapped when it fails, so clients never see a half-written repo.
+// Files are synced to disk before and after they are swapped, and a journal records the files
+// adopted and published, so a staging directory left by a crash is rolled back. (RecoverStaging)
+type Staging struct {
+	repo    string
+	dir     string
+	adopted map[string]string
+	removed []string
+	journal *os.File
+}
+
+// NewStaging creates a staging directory inside repo, so files can be renamed into the repo.
+// The staging directories a crash left in repo are recovered first.
+func NewStaging(repo string) (*Staging, error) {
+	if err := RecoverStaging(repo); err != nil {
+		return nil, err
+	}
+	dir, err := ioutil.TempDir(repo, StagingPrefix)
+	if err != nil {
+		return nil, err
+	}
+	s := &Staging{repo: repo, dir: dir, adopted: map[string]string{}}
+	host, _ := os.Hostname()
+	if err := s.record(journalRecord{PID: os.Getpid(), Host: host}); err != nil {
+		s.Abort()
+		return nil, err
+	}
+	return s, nil
+}
+
+// journalRecord represents a line of the journal of a staging directory.
+type journalRecord struct {
+	PID     int             `json:"pid,omitempty"`
+	Host    string          `json:"host,omitempty"`
+	Name    string          `json:"name,omitempty"` // a file adopted from Src.
+	Src     string          `json:"src,omitempty"`
+	State   string          `json:"state,omitempty"`
+	Names   []string        `json:"names,omitempty"` // the files published, in order.
+	Removed []string        `json:"removed,omitempty"`
+	Backups map[string]bool `json:"backups,omitempty"`
+}
+
+// record appends rec to the journal and syncs it to disk.
+func (s *Staging) record(rec journalRecord) error {
+	if s.journal == nil {
+		f, err := os.OpenFile(filepath.Join(s.dir, journalFile), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		s.journal = f
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := s.journal.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return s.journal.Sync()
+}
+
+// closeJournal closes the journal, so the staging directory can be removed.
+func (s *Staging) closeJournal() {
+	if s.journal != nil {
+		s.journal.Close()
+		s.journal = nil
+	}
+}
+
+// Dir returns the path of the staging directory.
+func (s *Staging) Dir() string {
+	return s.dir
+}
+
+// Path returns the staging path of the repo file name. (Packages.bz2)
+func (s *Staging) Path(name string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(name))
+}
+
+// WriteFile writes the repo file name to the staging directory. It is synced to disk when published.
+func (s *Staging) WriteFile(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path(name)), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.Path(name), data, 0644)
+}
+
+// Adopt moves the file src into the staging directory as the repo file name.
+// It is moved back to src when the staging directory is aborted.
+func (s *Staging) Adopt(src string, name string) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path(name)), 0755); err != nil {
+		return err
+	}
+	// The journal knows where the file comes from before it is moved, to move it back after a crash.
+	abs, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+	if err := s.record(journalRecord{Name: name, Src: abs}); err != nil {
+		return err
+	}
+	if err := os.Rename(src, s.Path(name)); err != nil {
+		return err
+	}
+	s.adopted[name] = abs
+	return nil
+}
+
+// Remove marks the repo file name to be removed once every staged file is published.
+func (s *Staging) Remove(name string) {
+	s.removed = append(s.removed, name)
+}
+
+// Abort removes the staging directory, moving adopted files back where they came from.
+// The repo is left untouched. The staging directory is kept when an adopted file cannot be moved back,
+// and recovered later. (RecoverStaging)
+func (s *Staging) Abort() {
+	s.closeJournal()
+	if s.moveBack() == nil {
+		os.RemoveAll(s.dir)
+	}
+}
+
+// moveBack moves the adopted files still in the staging directory back where they came from.
+func (s *Staging) moveBack() error {
+	var failed error
+	for name, src := range s.adopted {
+		if _, err := os.Stat(s.Path(name)); err != nil {
+			continue
+		}
+		os.MkdirAll(filepath.Dir(src), 0755)
+		if err := os.Rename(s.Path(name), src); err != nil && failed == nil {
+			failed = err
+		}
+	}
+	return failed
+}
+
+// PublishError is returned when publishing a staging directory fails. The repo files are restored.
+type PublishError struct {
+	Err error
+}
+
+// Error returns why publishing failed.
+func (e *PublishError) Error() string {
+	return "publishing failed, repo restored: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error, such as the *os.PathError of the file which failed.
+func (e *PublishError) Unwrap() error {
+	return e.Err
+}
+
+// publishRank orders the staged files so that index files are published after the debs they list,
+// and the Release files and their signatures are published last.
+func publishRank(name string) int {
+	base := path.Base(name)
+	switch {
+	case base == "Release":
+		return 3
+	case base == "Release.gpg" || base == "InRelease":
+		return 4
+	case strings.HasPrefix(name, "by-hash/") || strings.Contains(name, "/by-hash/"):
+		return 1
+	case strings.HasPrefix(base, "Packages"):
+		return 2
+	}
+	return 0
+}
+
+// Publish swaps every staged file into the repo and then removes the files marked for removal.
+// When any step fails, the repo files already replaced or removed are restored.
+// The staged files and their backups are synced to disk before the repo is touched, and the directories
+// of the repo after, so a crash leaves either the old repo, a repo to roll back, or the new repo.
+func (s *Staging) Publish() error {
+	var names, dirs []string
+	err := filepath.Walk(s.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(s.dir, path)
+		if info.IsDir() {
+			if rel == backupDir {
+				return filepath.SkipDir
+			}
+			dirs = append(dirs, path)
+			return nil
+		}
+		if rel != journalFile {
+			names = append(names, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		return publishRank(names[i]) < publishRank(names[j])
+	})
+	var removed []string
+	for _, name := range s.removed {
+		if _, err := os.Stat(filepath.Join(s.repo, filepath.FromSlash(name))); err == nil {
+			removed = append(removed, name)
+		}
+	}
+
+	// Back up the repo files first, and sync everything a rollback needs before the repo is touched.
+	backups := map[string]bool{}
+	for _, name := range append(append([]string{}, names...), removed...) {
+		backedUp, err := s.backup(name)
+		if err != nil {
+			return err
+		}
+		backups[name] = backedUp
+	}
+	for _, name := range names {
+		if err := syncPath(s.Path(name)); err != nil {
+			return err
+		}
+	}
+	filepath.Walk(filepath.Join(s.dir, backupDir), func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	for _, dir := range dirs {
+		syncDir(dir)
+	}
+	if err := s.record(journalRecord{State: stagingPublishing, Names: names, Removed: removed, Backups: backups}); err != nil {
+		return err
+	}
+
+	var done []string
+	rollback := func(err error) error {
+		for i := len(done) - 1; i >= 0; i-- {
+			s.restore(done[i], backups[done[i]])
+		}
+		return &PublishError{Err: err}
+	}
+	targets := map[string]bool{s.repo: true}
+	for _, name := range names {
+		target := filepath.Join(s.repo, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return rollback(err)
+		}
+		// Renaming over the old file replaces it atomically.
+		if err := os.Rename(s.Path(name), target); err != nil {
+			return rollback(err)
+		}
+		done = append(done, name)
+		for dir := filepath.Dir(target); !targets[dir]; dir = filepath.Dir(dir) {
+			targets[dir] = true
+		}
+	}
+	for _, name := range removed {
+		target := filepath.Join(s.repo, filepath.FromSlash(name))
+		if err := os.Remove(target); err != nil {
+			return rollback(err)
+		}
+		done = append(done, name)
+		targets[filepath.Dir(target)] = true
+	}
+	for dir := range targets {
+		syncDir(dir)
+	}
+	s.record(journalRecord{State: stagingDone})
+
+	s.adopted = map[string]string{}
+	s.closeJournal()
+	os.RemoveAll(s.dir)
+	return nil
+}
+
+// backup keeps a copy of the repo file name in the staging directory, if it exists.
+// A hard link is used when possible, so the repo file stays in place until it is replaced.
+func (s *Staging) backup(name string) (bool, error) {
+	target := filepath.Join(s.repo, filepath.FromSlash(name))
+	if _, err := os.Stat(target); err != nil {
+		return false, nil
+	}
+	backup := filepath.Join(s.dir, backupDir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(backup), 0755); err != nil {
+		return false, err
+	}
+	if err := os.Link(target, backup); err != nil {
+		if err := Copy(target, backup); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// syncPath syncs the file at path to disk.
+func syncPath(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		// Adopted files may be read only, syncing through a read only descriptor works but on Windows.
+		if f, err = os.Open(path); err != nil {
+			return err
+		}
+	}
+	defer f.Close()
+	if err := f.Sync(); err != nil && runtime.GOOS != "windows" {
+		return err
+	}
+	return nil
+}
+
+// syncDir syncs the directory dir to disk, so the files renamed into it stay there after a crash.
+// Not every system can sync directories, so errors are ignored.
+func syncDir(dir string) {
+	if f, err := os.Open(dir); err == nil {
+		f.Sync()
+		f.Close()
+	}
+}
+
+// RecoverStaging recovers the staging directories of repo which a crash left behind: a repo published
+// halfway is rolled back and the adopted files are moved back where they came from, before the staging
+// directory is removed. Staging directories of running processes are left alone.
+func RecoverStaging(repo string) error {
+	files, err := ioutil.ReadDir(repo)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, f := range files {
+		if !f.IsDir() || !strings.HasPrefix(f.Name(), StagingPrefix) {
+			continue
+		}
+		if err := recoverStaging(repo, filepath.Join(repo, f.Name()), f); err != nil {
+			return errors.New("unable to recover " + f.Name() + ": " + err.Error())
+		}
+	}
+	return nil
+}
+
+// recoverStaging recovers the staging directory dir of repo, if the process which created it is gone.
+func recoverStaging(repo string, dir string, info os.FileInfo) error {
+	records, err := readJournal(filepath.Join(dir, journalFile))
+	if os.IsNotExist(err) {
+		// Nothing is adopted or published before the journal is written, the directory only holds staged files.
+		if time.Since(info.ModTime()) > staleAge {
+			return os.RemoveAll(dir)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	s := &Staging{repo: repo, dir: dir, adopted: map[string]string{}}
+	var state string
+	var publishing journalRecord
+	host, _ := os.Hostname()
+	for _, rec := range records {
+		switch {
+		case rec.PID != 0:
+			if rec.Host != host || rec.PID == os.Getpid() || processAlive(rec.PID) {
+				return nil
+			}
+		case rec.Name != "":
+			s.adopted[rec.Name] = rec.Src
+		case rec.State == stagingPublishing:
+			publishing = rec
+		}
+		if rec.State != "" {
+			state = rec.State
+		}
+	}
+
+	if state == stagingPublishing {
+		// The files renamed into the repo are no longer staged, they are put back like a failed publish.
+		for i := len(publishing.Removed) - 1; i >= 0; i-- {
+			name := publishing.Removed[i]
+			if _, err := os.Stat(filepath.Join(repo, filepath.FromSlash(name))); os.IsNotExist(err) {
+				s.restore(name, true)
+			}
+		}
+		for i := len(publishing.Names) - 1; i >= 0; i-- {
+			name := publishing.Names[i]
+			if _, err := os.Stat(s.Path(name)); os.IsNotExist(err) {
+				s.restore(name, publishing.Backups[name])
+			}
+		}
+	}
+	if state != stagingDone {
+		if err := s.moveBack(); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(dir)
+}
+
+// readJournal reads the records of the journal at path. A record cut short by a crash is ignored.
+func readJournal(path string) ([]journalRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var records []journalRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		var rec journalRecord
+		if json.Unmarshal(scanner.Bytes(), &rec) == nil {
+			records = append(records, rec)
+		}
+	}
+	return records, scanner.Err()
+}
+
+// processAlive returns whether the process pid is running.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// On Windows, finding the process is enough.
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// restore undoes publishing the repo file name, putting back its backup or removing it.
+func (s *Staging) restore(name string, backedUp bool) {
+	target := filepath.Join(s.repo, filepath.FromSlash(name))
+	if !backedUp {
+		// The staged file goes back to the staging directory, so an adopted file can be moved back.
+		os.Rename(target, s.Path(name))
+		return
+	}
+	if _, err := os.Stat(s.Path(name)); os.IsNotExist(err) {
+		os.Rename(target, s.Path(name))
+	}
+	os.Rename(filepath.Join(s.dir, backupDir, filepath.FromSlash(name)), target)
+}
@@ -0,0 +1,190 @@
+package afutil
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp/clearsign"
+	"github.com/hako/afto/deb"
+	"github.com/hako/afto/release"
+)
+
+// Signature states of a repo.
+const (
+	SignatureNone     = "unsigned"
+	SignatureSigned   = "signed"
+	SignatureOutdated = "outdated" // the signatures are older than the Release file.
+)
+
+// RepoStatus represents the state of a repo, as afto status reports it.
+type RepoStatus struct {
+	Dir       string    `json:"dir"`
+	Packages  int       `json:"packages"`
+	Debs      int       `json:"debs"`
+	Generated time.Time `json:"generated"`
+	Signature string    `json:"signature"`
+	Stale     bool      `json:"stale"`
+	Reasons   []string  `json:"reasons"`
+}
+
+// RepoState returns the status of the repo dir. Its indexes are stale when a deb is not in Packages,
+// a Packages entry has no deb, or a deb changed since Packages was generated. The Packages files of every suite count.
+// Only the debs modified after the Date of Release and their cache entry are hashed, so this is cheap.
+// (afto verify hashes every deb)
+func RepoState(dir string) (*RepoStatus, error) {
+	entries, err := RepoPackages(dir)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "Release"))
+	if err != nil {
+		return nil, err
+	}
+	rel, err := release.NewRelease().ParseString(string(data))
+	if err != nil {
+		return nil, err
+	}
+	status := &RepoStatus{Dir: dir, Packages: len(entries), Generated: rel.Date(), Reasons: []string{}}
+	status.Signature = signatureState(dir, data)
+
+	// Debs against Packages, by Filename, Size and, for the debs modified since, SHA256.
+	listed := map[string]*deb.Packages{}
+	for _, p := range entries {
+		listed[filepath.Clean(filepath.FromSlash(p.Filename()))] = p
+	}
+	cache := LoadCache(filepath.Join(dir, CacheFile))
+	debs, err := repoDebs(dir)
+	if err != nil {
+		return nil, err
+	}
+	status.Debs = len(debs)
+	present := map[string]bool{}
+	for _, d := range debs {
+		present[d.Name] = true
+		p, ok := listed[d.Name]
+		switch {
+		case !ok:
+			status.add(filepath.ToSlash(d.Name) + " is not in Packages")
+		case int64(p.Size()) != d.Size():
+			status.add(filepath.ToSlash(d.Name) + " is " + strconv.FormatInt(d.Size(), 10) + " bytes, Packages says " + strconv.Itoa(p.Size()))
+		case modified(d, rel.Date(), cache):
+			// A deb copied with a new modification time is not stale, its hash still matches.
+			sum, err := sha256File(filepath.Join(dir, d.Name))
+			if err != nil || sum != p.SHA256() {
+				status.add(filepath.ToSlash(d.Name) + " changed since Packages was generated")
+			}
+		}
+	}
+	for name := range listed {
+		if !present[name] {
+			status.add(filepath.ToSlash(name) + " is in Packages but does not exist")
+		}
+	}
+	sort.Strings(status.Reasons)
+	return status, nil
+}
+
+// modified returns whether the deb d was modified after generated, the Date of Release,
+// and after the modification time of its cache entry.
+func modified(d repoDebFile, generated time.Time, cache *Cache) bool {
+	// The Date of Release is truncated to the second.
+	if !d.ModTime().After(generated.Add(time.Second)) {
+		return false
+	}
+	e, ok := cache.Entries[filepath.ToSlash(d.Name)]
+	return !ok || e.ModTime != d.ModTime().UnixNano()
+}
+
+// add records why the indexes of the repo are stale.
+func (s *RepoStatus) add(reason string) {
+	s.Stale = true
+	s.Reasons = append(s.Reasons, reason)
+}
+
+// WalkRepos finds the repos under root, the directories which ParseDir accepts, and returns their status
+// sorted by directory. Hidden directories and the pool/, dists/ and by-hash/ directories of repos are skipped.
+// A repo whose files cannot be read is reported as stale, with the error as the reason.
+func WalkRepos(root string) ([]*RepoStatus, error) {
+	var repos []*RepoStatus
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		name := info.Name()
+		if path != root && (strings.HasPrefix(name, ".") || name == "pool" || name == "dists" || name == "by-hash") {
+			return filepath.SkipDir
+		}
+		if ok, _ := ParseDir(path); !ok {
+			return nil
+		}
+		status, err := RepoState(path)
+		if err != nil {
+			status = &RepoStatus{Dir: path, Signature: SignatureNone, Stale: true, Reasons: []string{err.Error()}}
+		}
+		repos = append(repos, status)
+		return nil
+	})
+	return repos, err
+}
+
+// repoDebFile represents a deb of a repo, named by its path in the repo.
+type repoDebFile struct {
+	os.FileInfo
+	Name string
+}
+
+// repoDebs returns the debs in the root and the pool of the repo dir.
+func repoDebs(dir string) ([]repoDebFile, error) {
+	var debs []repoDebFile
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if !f.IsDir() && filepath.Ext(f.Name()) == ".deb" {
+			debs = append(debs, repoDebFile{FileInfo: f, Name: f.Name()})
+		}
+	}
+	pool := filepath.Join(dir, "pool")
+	if _, err := os.Stat(pool); err != nil {
+		return debs, nil
+	}
+	err = filepath.Walk(pool, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && filepath.Ext(path) == ".deb" {
+			rel, _ := filepath.Rel(dir, path)
+			debs = append(debs, repoDebFile{FileInfo: info, Name: rel})
+		}
+		return nil
+	})
+	return debs, err
+}
+
+// signatureState returns whether the Release file of the repo dir, holding data, is signed,
+// and whether its signatures are older than it. Signatures are not verified. (afto verify checks them)
+func signatureState(dir string, data []byte) string {
+	state := SignatureNone
+	if inRelease, err := ioutil.ReadFile(filepath.Join(dir, "InRelease")); err == nil {
+		block, _ := clearsign.Decode(inRelease)
+		if block == nil || !bytes.Equal(block.Plaintext, data) {
+			return SignatureOutdated
+		}
+		state = SignatureSigned
+	}
+	if sig, err := os.Stat(filepath.Join(dir, "Release.gpg")); err == nil {
+		rel, err := os.Stat(filepath.Join(dir, "Release"))
+		if err != nil || sig.ModTime().Before(rel.ModTime()) {
+			return SignatureOutdated
+		}
+		state = SignatureSigned
+	}
+	return state
+}
@@ -0,0 +1,221 @@
+package afutil
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/hako/afto/release"
+)
+
+// Kinds of problems found when verifying a repo.
+const (
+	ProblemDeb       = "deb"       // a deb does not match its Packages entry.
+	ProblemDangling  = "dangling"  // a Packages entry refers to a deb which does not exist.
+	ProblemOrphan    = "orphan"    // a deb is not listed in Packages.
+	ProblemIndex     = "index"     // an index file does not match the Release file.
+	ProblemSignature = "signature" // the Release file signature is missing or bad.
+)
+
+// VerifyProblem represents a single problem found in a repo.
+type VerifyProblem struct {
+	Kind    string `json:"kind"`
+	File    string `json:"file"`
+	Message string `json:"message"`
+}
+
+// String returns the problem in a single line. (deb: ./foo.deb: MD5sum mismatch)
+func (p VerifyProblem) String() string {
+	return p.Kind + ": " + p.File + ": " + p.Message
+}
+
+// VerifyReport represents the result of verifying a repo.
+type VerifyReport struct {
+	Repo     string          `json:"repo"`
+	Packages int             `json:"packages"`
+	Indexes  int             `json:"indexes"`
+	Signed   bool            `json:"signed"`
+	OK       bool            `json:"ok"`
+	Problems []VerifyProblem `json:"problems"`
+
+	hashed map[string]bool // the index files listed in a Release file.
+}
+
+// add records a problem in the report.
+func (r *VerifyReport) add(kind string, file string, msg string) {
+	r.Problems = append(r.Problems, VerifyProblem{Kind: kind, File: file, Message: msg})
+	r.OK = false
+}
+
+// VerifyRepo audits the repo dir end to end. Every deb is re-hashed against its Packages entry,
+// every index file is re-hashed against the Release file and debs missing from Packages are flagged.
+// The Packages and Release files of the suites under dists/ are checked like the ones of the repo root.
+// When keyring is not nil, the Release.gpg and InRelease signatures are checked against it.
+// An error is only returned when the repo cannot be read at all.
+func VerifyRepo(dir string, keyring openpgp.KeyRing) (*VerifyReport, error) {
+	entries, err := RepoPackages(dir)
+	if err != nil {
+		return nil, err
+	}
+	report := &VerifyReport{Repo: dir, Packages: len(entries), OK: true, Problems: []VerifyProblem{}, hashed: map[string]bool{}}
+
+	// Debs against Packages.
+	listed := map[string]bool{}
+	for _, p := range entries {
+		if p.Filename() == "" {
+			report.add(ProblemDeb, p.Package(), "Packages entry has no Filename")
+			continue
+		}
+		name := filepath.Clean(filepath.FromSlash(p.Filename()))
+		listed[name] = true
+		size, sums, err := hashDeb(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			report.add(ProblemDangling, p.Filename(), "listed for "+p.Package()+" "+p.Version()+" but does not exist")
+			continue
+		}
+		if err != nil {
+			report.add(ProblemDeb, p.Filename(), err.Error())
+			continue
+		}
+		if p.Field("Size") != "" && int64(p.Size()) != size {
+			report.add(ProblemDeb, p.Filename(), "Size is "+strconv.FormatInt(size, 10)+", Packages says "+strconv.Itoa(p.Size()))
+		}
+		for _, field := range []string{"MD5sum", "SHA1", "SHA256"} {
+			if want := p.Field(field); want != "" && !strings.EqualFold(want, sums[field]) {
+				report.add(ProblemDeb, p.Filename(), field+" mismatch")
+			}
+		}
+	}
+
+	// Debs which are not in Packages. Leftover staging directories and the cache are not part of the repo.
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() && strings.HasPrefix(info.Name(), StagingPrefix) {
+			return filepath.SkipDir
+		}
+		if info.IsDir() || info.Name() == CacheFile || filepath.Ext(path) != ".deb" {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		if !listed[rel] {
+			report.add(ProblemOrphan, "./"+filepath.ToSlash(rel), "not listed in Packages")
+		}
+		return nil
+	})
+
+	// Index files and signatures against every Release file.
+	dirs, err := ReleaseDirs(dir)
+	if err != nil {
+		return nil, err
+	}
+	signed := keyring != nil
+	for _, d := range dirs {
+		ok, err := verifyReleaseDir(report, dir, d, keyring)
+		if err != nil {
+			return nil, err
+		}
+		signed = signed && ok
+	}
+	report.Signed = signed
+	for _, name := range append([]string{"Packages"}, PackagesFiles(Compressions)...) {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil && !report.hashed[name] {
+			report.add(ProblemIndex, name, "not listed in Release")
+		}
+	}
+
+	sort.SliceStable(report.Problems, func(i, j int) bool {
+		return report.Problems[i].File < report.Problems[j].File
+	})
+	return report, nil
+}
+
+// verifyReleaseDir checks the index files listed in the Release file of the directory rel of the repo dir,
+// and its signatures against keyring when it is not nil. It returns whether the Release file is signed.
+func verifyReleaseDir(report *VerifyReport, dir string, rel string, keyring openpgp.KeyRing) (bool, error) {
+	prefix := ""
+	if rel != "" {
+		prefix = filepath.ToSlash(rel) + "/"
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, rel, "Release"))
+	if err != nil {
+		return false, err
+	}
+	r, err := release.NewRelease().ParseString(string(data))
+	if err != nil {
+		return false, errors.New(prefix + "Release: " + err.Error())
+	}
+	report.Indexes += len(r.Files())
+	for _, f := range r.Files() {
+		name := prefix + f.Name
+		report.hashed[name] = true
+		index, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			report.add(ProblemIndex, name, "listed in Release but cannot be read")
+			continue
+		}
+		actual := release.NewRelease()
+		actual.AddIndexFile(f.Name, index)
+		if len(index) != f.Size {
+			report.add(ProblemIndex, name, "Size is "+strconv.Itoa(len(index))+", Release says "+strconv.Itoa(f.Size))
+		}
+		for _, field := range release.HashFields {
+			if want := f.Hash(field); want != "" && !strings.EqualFold(want, actual.Files()[0].Hash(field)) {
+				report.add(ProblemIndex, name, field+" mismatch")
+			}
+		}
+	}
+	if keyring == nil {
+		return false, nil
+	}
+
+	signatures, bad := 0, 0
+	for _, sig := range []struct {
+		name   string
+		verify func(string, openpgp.KeyRing) error
+	}{{"Release.gpg", verifyDetached}, {"InRelease", verifyInRelease}} {
+		if _, err := os.Stat(filepath.Join(dir, rel, sig.name)); err != nil {
+			continue
+		}
+		signatures++
+		if err := sig.verify(filepath.Join(dir, rel), keyring); err != nil {
+			report.add(ProblemSignature, prefix+sig.name, strings.TrimPrefix(err.Error(), sig.name+": "))
+			bad++
+		}
+	}
+	if signatures == 0 {
+		report.add(ProblemSignature, prefix+"Release", "repo is not signed")
+	}
+	return signatures > 0 && bad == 0, nil
+}
+
+// hashDeb returns the size of the deb at path and its MD5sum, SHA1 and SHA256 hashes.
+func hashDeb(path string) (int64, map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer f.Close()
+
+	md5h, sha1h, sha256h := md5.New(), sha1.New(), sha256.New()
+	size, err := io.Copy(io.MultiWriter(md5h, sha1h, sha256h), f)
+	if err != nil {
+		return 0, nil, err
+	}
+	return size, map[string]string{
+		"MD5sum": fmt.Sprintf("%x", md5h.Sum(nil)),
+		"SHA1":   fmt.Sprintf("%x", sha1h.Sum(nil)),
+		"SHA256": fmt.Sprintf("%x", sha256h.Sum(nil)),
+	}, nil
+}
@@ -1,20 +1,24 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"text/tabwriter"
+	"time"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
 	"github.com/docopt/docopt-go"
 	"github.com/fatih/color"
 	"github.com/gorilla/handlers"
 	"github.com/hako/afto/afutil"
+	"github.com/hako/afto/deb"
+	"github.com/hako/afto/repo"
 	"github.com/rjeczalik/notify"
 )
 
@@ -23,11 +27,17 @@ var (
 	buildHash = "0"
 	buildDate string
 
-	port     = "2468"
-	repoPath = ""
-	file     = ""
-
-	aftoUnexpectedError = "an unexpected error occurred while updating. Make sure the path to the .deb file is correct"
+	port       = "2468"
+	repoPath   = ""
+	file       = ""
+	force      = false
+	component  = ""
+	suite      = ""
+	configFile = afutil.ConfigFile
+	config     = afutil.DefaultConfig()
+
+	// watchDelay is how long the watcher waits for deb files to settle before regenerating.
+	watchDelay = 2 * time.Second
 )
 
 var header = `
@@ -54,35 +64,56 @@ var usage = `afto ` + version + ` (` + buildHash + `)
 built on: ` + buildDate + `
 
 Usage:
-  afto new <name>
-  afto serve <dir> [-w | --watch] [-p <port> | --port <port>]
-  afto update -r <name> [-f <file> | --file <file>] 
+  afto new <name> [-z <formats>] [--suite <suite>] [--component <component>] [--force] [--config <file>]
+  afto serve <dir> [-w | --watch] [-p <port> | --port <port>] [--config <file>]
+  afto update -r <name> [-f <file> | --file <file>] [-z <formats>] [--suite <suite>] [--component <component>] [--force] [--config <file>]
+  afto add <name> <deb>... [--suite <suite>] [--component <component>] [--force] [--config <file>]
+  afto remove <name> <package> [--arch <arch>] [--suite <suite>] [--force] [--config <file>]
+  afto list <name> [<pattern>] [--arch <arch>] [--section <section>] [--suite <suite>] [--json] [--config <file>]
+  afto show <name> <package> [--suite <suite>] [--json] [--config <file>]
+  afto promote <package> <version> --from <suite> --to <suite> [-r <name>] [--force] [--config <file>]
+  afto prune <dir> [--suite <suite>] [--force] [--config <file>]
+  afto status [<dir>] [--json]
+  afto verify <dir> [-k <keyfile> | --key <keyfile>] [--json]
   afto [-c <file> | --control <file>]
-  afto [-s <dir> | --sign <dir>]
+  afto [-s <dir> | --sign <dir>] [-k <keyfile> | --key <keyfile>] [--config <file>]
 
 options:
   -c, --control  Specify control file to use.
   -p, --port     Specify port number for afto.
+  -z, --compress <formats>  Specify Packages compressions. (default: bz2,gz,xz,zst)
+  --force        Publish despite unsatisfiable dependencies or without signing a signed repo, or update to a version which is not newer.
+  --config <file>  Specify repo config file to use. (default: afto.yaml in the repo, or the current directory)
+  --suite <suite>  Specify the suite of the repo to generate or update. (default: the first in afto.yaml)
+  --from <suite>   Specify the suite to promote a package from.
+  --to <suite>     Specify the suite to promote a package to.
+  --arch <arch>    Specify the architecture of the packages to list or remove. (default: every architecture)
+  --section <section>  Specify the section of the packages to list.
+  --component <component>  Specify the component new debs go to in the pool layout. (default: the first in afto.yaml)
+  -k, --key <keyfile>  Specify key or keyring to sign with, or public key to verify with.
+  --json         Print a machine-readable report, package list or status.
   -h, --help     Show this screen.
   --version      Show version.
 
 commands:
   new             Generate a new Cydia repo.
-  serve           Serve the Cydia repo.`
-
-// AftoRepo represents a cydia repo with a name.
-type AftoRepo struct {
-	Name      string
-	Debs      []string
-	Cmd       string
-	SingleDeb string
-}
+  serve           Serve the Cydia repo.
+  verify          Verify the hashes and signatures of a Cydia repo.
+  status          Report on every Cydia repo under a directory. (default: the current directory)
+  add             Add debs, directories of debs or globs to a Cydia repo.
+  list            List the packages of a Cydia repo, optionally matching a pattern.
+  show            Show the Packages entries of a package, or of a version of it with <package>=<version>.
+  remove          Remove a package, or a version of it with <package>=<version>, from a Cydia repo.
+  promote         Move a package version from a suite of a Cydia repo to another.
+  prune           Remove the old package versions of a Cydia repo which its retention policy expires.`
 
 func main() {
 	// Parse flags.
+	// Running afto on its own reports on the repos under the current directory.
 	if len(os.Args) == 1 {
-		fmt.Println(usage)
-		os.Exit(1)
+		log.SetPrefix("afto: ")
+		log.SetFlags(2)
+		walkRepos(".", false)
 	}
 
 	// Parse options with docopt.
@@ -90,6 +121,22 @@ func main() {
 	log.SetPrefix("afto: ")
 	log.SetFlags(2)
 
+	// Afto --config option (the repo config file, afto.yaml in the repo or the current directory by default).
+	if path, ok := opts["--config"].(string); ok {
+		if _, err := os.Stat(path); err != nil {
+			log.Fatalln(err)
+		}
+		configFile = path
+	} else {
+		configFile = repoConfig(opts)
+	}
+	cfg, err := afutil.LoadConfig(configFile)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	config = cfg
+	port = config.Port
+
 	// Afto -p option (port for afto server to run on).
 	if opts["-p"] == true || opts["--port"] == true {
 		argport := opts["<port>"].(string)
@@ -102,13 +149,42 @@ func main() {
 		file = optsfile
 	}
 
+	// Afto --force option (allow downgrades and reinstalls on update).
+	if opts["--force"] == true {
+		force = true
+	}
+
+	// Afto --component option (the component new debs go to in the pool layout).
+	if name, ok := opts["--component"].(string); ok {
+		component = name
+	}
+
+	// Afto --suite option (the suite of the repo to generate or update).
+	if name, ok := opts["--suite"].(string); ok {
+		suite = name
+	}
+
+	// Afto -z option (Packages compressions).
+	if formats, ok := opts["--compress"].(string); ok {
+		comps, err := afutil.ParseCompressions(formats)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		config.Compressions = comps
+	}
+
 	// Afto -s option (signing the repo).
 	if opts["-s"] == true || opts["--sign"] == true {
-		repo := opts["<dir>"].(string)
-		log.Println("signing repo \"" + repo + "\"")
-		err := afutil.SignRepo(repo)
-		if err != nil {
-			log.Fatalln(err.Error())
+		dir := opts["<dir>"].(string)
+		if keyFile, ok := opts["--key"].(string); ok {
+			config.SigningKey = keyFile
+		}
+		passphrase, perr := afutil.ReadPassphrase(config.PassphraseFile)
+		if perr != nil {
+			log.Fatalln(perr)
+		}
+		if err := openRepo(dir).Sign(config.SigningKey, passphrase); err != nil {
+			fail(err)
 		}
 		log.Println("repo successfully signed!")
 		os.Exit(0)
@@ -117,34 +193,141 @@ func main() {
 	// Afto new command.
 	if opts["new"] == true {
 		name := opts["<name>"].(string)
-		af := &AftoRepo{Name: name, Cmd: "new"}
-		af.newRepo()
+		if _, err := openSuite(name).Create("."); err != nil {
+			fail(err)
+		}
 		os.Exit(0)
 	}
 
 	// Afto update command.
 	if opts["update"] == true {
-		var af *AftoRepo
 		name := opts["<name>"].(string)
+		if _, err := openSuite(name).Update(file); err != nil {
+			fail(err)
+		}
+		os.Exit(0)
+	}
 
-		if file != "" {
-			af = &AftoRepo{Name: name, Cmd: "update", SingleDeb: file}
-		} else {
-			af = &AftoRepo{Name: name, Cmd: "update"}
+	// Afto add command.
+	if opts["add"] == true {
+		name := opts["<name>"].(string)
+		if _, err := openSuite(name).Add(opts["<deb>"].([]string)...); err != nil {
+			fail(err)
+		}
+		log.Println("debs successfully added!")
+		os.Exit(0)
+	}
+
+	// Afto remove command (<package> or <package>=<version>).
+	if opts["remove"] == true {
+		name := opts["<name>"].(string)
+		pkg, version := opts["<package>"].(string), ""
+		if i := strings.Index(pkg, "="); i >= 0 {
+			pkg, version = pkg[:i], pkg[i+1:]
+		}
+		arch, _ := opts["--arch"].(string)
+		if _, err := openSuite(name).Remove(pkg, version, arch); err != nil {
+			if e, ok := err.(*repo.DependencyError); ok {
+				log.Fatalln(e.Error() + ", use --force to remove anyway.")
+			}
+			fail(err)
 		}
-		af.updateRepo()
+		log.Println("\"" + pkg + "\" successfully removed!")
 		os.Exit(0)
 	}
 
+	// Afto list command.
+	if opts["list"] == true {
+		name := opts["<name>"].(string)
+		var filter afutil.PackageFilter
+		filter.Pattern, _ = opts["<pattern>"].(string)
+		filter.Architecture, _ = opts["--arch"].(string)
+		filter.Section, _ = opts["--section"].(string)
+		entries, err := openSuite(name).List(filter)
+		if err != nil {
+			fail(err)
+		}
+		listPackages(entries, opts["--json"] == true)
+		os.Exit(0)
+	}
+
+	// Afto show command (<package> or <package>=<version>).
+	if opts["show"] == true {
+		name := opts["<name>"].(string)
+		pkg, version := opts["<package>"].(string), ""
+		if i := strings.Index(pkg, "="); i >= 0 {
+			pkg, version = pkg[:i], pkg[i+1:]
+		}
+		entries, err := openSuite(name).Show(pkg, version)
+		if err != nil {
+			fail(err)
+		}
+		showPackages(entries, opts["--json"] == true)
+		os.Exit(0)
+	}
+
+	// Afto promote command.
+	if opts["promote"] == true {
+		name, ok := opts["<name>"].(string)
+		if !ok {
+			name = "."
+		}
+		pkg, version := opts["<package>"].(string), opts["<version>"].(string)
+		if _, err := openRepo(name).Promote(pkg, version, opts["--from"].(string), opts["--to"].(string)); err != nil {
+			fail(err)
+		}
+		log.Println("successfully promoted \"" + pkg + "\" " + version + "!")
+		os.Exit(0)
+	}
+
+	// Afto prune command (every suite, unless --suite is given).
+	if opts["prune"] == true {
+		dir := opts["<dir>"].(string)
+		suites := openSuites(dir)
+		if suite != "" {
+			suites = []*repo.Repo{openSuite(dir)}
+		}
+		for _, r := range suites {
+			result, err := r.Prune()
+			if err != nil {
+				fail(err)
+			}
+			for _, c := range result.Changes {
+				log.Println(c.String())
+			}
+		}
+		log.Println("successfully pruned repo!")
+		os.Exit(0)
+	}
+
+	// Afto status command.
+	if opts["status"] == true {
+		dir, ok := opts["<dir>"].(string)
+		if !ok {
+			dir = "."
+		}
+		walkRepos(dir, opts["--json"] == true)
+	}
+
+	// Afto verify command.
+	if opts["verify"] == true {
+		dir := opts["<dir>"].(string)
+		keyFile, _ := opts["--key"].(string)
+		verifyRepo(dir, keyFile, opts["--json"] == true)
+	}
+
 	// Afto serve command.
 	if opts["serve"] == true {
 		// Parse the directory and fetch the final path to serve the repo.
 		// set the repo path to the final path.
 		var dir = opts["<dir>"].(string)
-		finalPath, err := afutil.GetRepo(dir)
+		r := openRepo(dir)
+		if _, err := afutil.GetRepo(r.Dir()); err != nil {
+			log.Fatalln(err.Error())
+		}
+		finalPath, err := filepath.Abs(dir)
 		if err != nil {
 			log.Fatalln(err.Error())
-			os.Exit(1)
 		}
 
 		repoPath = finalPath
@@ -156,25 +339,14 @@ func main() {
 		fmt.Println("afto is watching & listening for connections on port " + port)
 
 		// Add middleware.
-		mx := http.FileServer(http.Dir(repoPath))
+		mx := hideStaging(http.FileServer(http.Dir(repoPath)))
 		loggingHandler := handlers.LoggingHandler(os.Stdout, mx)
 
 		// Afto -w option (for watching the chosen directory).
 		if opts["-w"] == true || opts["--watch"] == true {
-			log.Println("watching the " + filepath.Base(repoPath) + " folder.")
-
-			// Spin up a goroutine for the file watcher.
-			go func() {
-				n := make(chan notify.EventInfo, 1)
-				// We only want the rename notification.
-				notify.Watch(repoPath, n, notify.Rename)
-				defer notify.Stop(n)
-				for {
-					ev := <-n
-					ev.Event()
-					regenerateRepo(repoPath)
-				}
-			}()
+			// Spin up a goroutine for the file watcher, new debs go to the default suite.
+			log.Println("watching the " + filepath.Base(r.Dir()) + " folder.")
+			go watchRepo(r)
 		}
 
 		// Spin up a goroutine for the repo server.
@@ -190,241 +362,294 @@ func main() {
 	}
 }
 
-// walkRepos checks multiple directories to see if they have the required files of
-// a cydia repo. (running afto on its own triggers this.)
-func walkRepos() {
-
+// hideStaging answers not found for the staging directories and the cache of the repo,
+// which are not part of the published repo.
+func hideStaging(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		for _, name := range strings.Split(req.URL.Path, "/") {
+			if strings.HasPrefix(name, afutil.StagingPrefix) || name == afutil.CacheFile {
+				http.NotFound(w, req)
+				return
+			}
+		}
+		h.ServeHTTP(w, req)
+	})
 }
 
-// checkReqs checks for dpkg and deb files.
-func (af *AftoRepo) checkReqs() {
-	// Check for the dpkg command.
-	err := afutil.CheckDpkg()
+// walkRepos checks multiple directories to see if they have the required files of
+// a cydia repo. (running afto on its own triggers this.)
+// Every repo found under dir is reported with its packages, generation time, signature and
+// whether its indexes are stale, exiting non-zero when none is found or any is stale.
+func walkRepos(dir string, asJSON bool) {
+	repos, err := afutil.WalkRepos(dir)
 	if err != nil {
-		log.Println(err)
-		// Now check for the compatible platform.
-		message, err := afutil.DetectPlatform()
-		if err != nil {
-			log.Fatalln(err)
-		}
-		fmt.Println(message)
+		log.Fatalln(err)
 	}
-	// Check for deb files. De(b)pending on the command given.
-	log.Println("checking for deb files...")
-	if af.Cmd == "new" {
-		debs, err := afutil.CheckDeb()
-		if err != nil {
-			log.Fatalln(err)
-		}
-		log.Println(strconv.Itoa(len(debs)) + " deb file(s) found.")
-		af.Debs = debs
+	if len(repos) == 0 {
+		log.Fatalln("no repos found in \"" + dir + "\". (see afto --help)")
 	}
-	if af.Cmd == "update" {
-		// We must compare debfile with the repo debfile. (Needs refactoring.)
-		if file != "" {
 
-			deb, err := afutil.CheckDebWithFile(af.SingleDeb)
-			if err != nil {
-				log.Fatalln(err)
+	stale := false
+	for _, r := range repos {
+		stale = stale || r.Stale
+	}
+	if asJSON {
+		out, _ := json.MarshalIndent(repos, "", "  ")
+		fmt.Println(string(out))
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "Repo\tPackages\tDebs\tGenerated\tSignature\tIndexes")
+		for _, r := range repos {
+			indexes := "up to date"
+			if r.Stale {
+				indexes = "stale"
 			}
-			log.Println("deb file: \"" + filepath.Base(af.SingleDeb) + "\" found.")
-			af.Debs = append(af.Debs, deb)
-		}
-		debs, err := afutil.CheckDebWithPath(af.Name)
-		if err != nil {
-			log.Fatalln(err)
+			generated := "-"
+			if !r.Generated.IsZero() {
+				generated = r.Generated.Local().Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintln(w, r.Dir+"\t"+strconv.Itoa(r.Packages)+"\t"+strconv.Itoa(r.Debs)+"\t"+generated+"\t"+r.Signature+"\t"+indexes)
 		}
-		log.Println(strconv.Itoa(len(debs)) + " deb file(s) found.")
-		for _, d := range debs {
-			af.Debs = append(af.Debs, af.Name+"/"+d)
+		w.Flush()
+		for _, r := range repos {
+			for _, reason := range r.Reasons {
+				fmt.Println(r.Dir + ": " + reason)
+			}
 		}
 	}
+	if stale {
+		os.Exit(1)
+	}
+	os.Exit(0)
 }
 
-// newRepo generates a new cydia compatible repo.
-func (af *AftoRepo) newRepo() {
-	var body string
-	af.checkReqs()
-	log.Println("generating repo: \"" + af.Name + "\"")
-	os.Mkdir(af.Name, 0755)
-	// Execute dpkg script.
-	direrr := af.executeDpkgScript()
-	if direrr != nil {
-		log.Fatalln(direrr)
-	}
-	log.Println("generated Packages file.")
-	// Execute bzip command.
-	bzerr := afutil.BzipPackages()
-	if bzerr != nil {
-		log.Fatalln(bzerr)
-	}
-	log.Println("bzipped Packages file.")
-	// Create Release file.
-	rfile, rfilerr := afutil.ReleaseFile("afto beta repo", "apt.afto.repo", "A default repo generated by afto", "afto", "beta")
-	if rfilerr != nil {
-		log.Fatalln(rfilerr)
-	}
-	rf, rferr := os.Create(af.Name + "/Release")
-	if rferr != nil {
-		log.Fatalln(rferr)
-	}
-	rf.WriteString(rfile)
-	log.Println("created Release file.")
-
-	htmlFile, hterr := os.Create("index.html")
-	if hterr != nil {
-		log.Println(hterr)
-	}
-
-	// Restore the icons too.
-	cyiconerr := RestoreAsset(".", "CydiaIcon.png")
-	if cyiconerr != nil {
-		log.Fatalln(cyiconerr)
-	}
-	cyicon2err := RestoreAsset(".", "CydiaIcon@2x.png")
-	if cyicon2err != nil {
-		log.Fatalln(cyicon2err)
-	}
-	cyicon3err := RestoreAsset(".", "CydiaIcon@3x.png")
-	if cyicon3err != nil {
-		log.Fatalln(cyicon3err)
-	}
-
-	// Move files to repo & generate HTML file.
-	os.Rename("Packages", af.Name+"/Packages")
-	os.Rename("Packages.bz2", af.Name+"/Packages.bz2")
-	os.Rename("CydiaIcon.png", af.Name+"/CydiaIcon.png")
-	os.Rename("CydiaIcon@2x.png", af.Name+"/CydiaIcon@2x.png")
-	os.Rename("CydiaIcon@3x.png", af.Name+"/CydiaIcon@3x.png")
-	for _, deb := range af.Debs {
-		os.Rename(deb, af.Name+"/"+deb)
-		body += fmt.Sprintln(`<pre><a href="` + deb + `">` + deb + `</a></pre>`)
-	}
-	htmlFile.WriteString(header + body + footer)
-	os.Rename("index.html", af.Name+"/index.html")
-}
-
-// updateRepo updates all the packages that exist in the current repo.
-func (af *AftoRepo) updateRepo() {
-	af.checkReqs()
-	log.Println("updating repo: \"" + af.Name + "\"")
-	path, err := afutil.GetRepo(af.Name)
+// verifyRepo audits the repo dir and prints a report, exiting non-zero when it has problems.
+func verifyRepo(dir string, keyFile string, asJSON bool) {
+	// Without a key the KeyRing stays nil, which skips the signature checks.
+	var keyring openpgp.KeyRing
+	if keyFile != "" {
+		k, err := afutil.ReadKeyRing(keyFile)
+		if err != nil {
+			log.Fatalln(keyFile + ": " + err.Error())
+		}
+		keyring = k
+	}
+	report, err := afutil.VerifyRepo(dir, keyring)
 	if err != nil {
 		log.Fatalln(err)
 	}
 
-	// The debfile we are comparing to in -r <deb>
-	inputDeb, err := afutil.ParseDeb(file)
-	if err != nil {
-		log.Fatalf(aftoUnexpectedError)
+	if asJSON {
+		out, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(out))
+	} else {
+		for _, p := range report.Problems {
+			fmt.Println(p.String())
+		}
+		log.Println(strconv.Itoa(report.Packages) + " package(s), " + strconv.Itoa(report.Indexes) + " index file(s) and " + strconv.Itoa(len(report.Problems)) + " problem(s) found.")
 	}
-	var newDeb, name, version, oldDeb = false, "", "", ""
+	if !report.OK {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
 
-	// The debfile we are comparing against <repo>
-	for _, deb := range af.Debs {
-		repodeb, err := afutil.ParseDeb(deb)
-		if err != nil {
-			log.Fatalf(aftoUnexpectedError)
-		}
-		if repodeb.Name() == inputDeb.Name() && repodeb.Version() != inputDeb.Version() {
-			newDeb = true
-			name = inputDeb.Name()
-			version = inputDeb.Version()
-			oldDeb = deb
+// repoConfig returns the config file of the repo the command works on, <name> or <dir>:
+// afto.yaml in the repo when it has one, otherwise afto.yaml in the current directory.
+func repoConfig(opts docopt.Opts) string {
+	dir, ok := opts["<name>"].(string)
+	if !ok {
+		dir, ok = opts["<dir>"].(string)
+	}
+	if ok {
+		path := filepath.Join(dir, afutil.ConfigFile)
+		if _, err := os.Stat(path); err == nil {
+			return path
 		}
 	}
-	if newDeb != true {
-		log.Println("No update is available.")
-		os.Exit(0)
-	}
+	return afutil.ConfigFile
+}
 
-	// Copy updated deb outside of folder.
-	log.Println("Update is available for \"" + name + "\" version " + version)
-	stripedRepo := strings.Replace(path, af.Name, "", -1)
-	e := afutil.Copy(af.SingleDeb, stripedRepo+filepath.Base(af.SingleDeb))
+// openRepo opens the repo at dir with the config, flags, icons and index.html of afto.
+func openRepo(dir string) *repo.Repo {
+	r := repo.New(dir, config)
+	r.SetForce(force)
+	r.SetComponent(component)
+	r.SetAssets(Asset)
+	r.SetIndex(indexHTML)
+	r.SetLogger(log.New(os.Stderr, "afto: ", log.Ltime))
+	return r
+}
+
+// openSuite opens the suite of the repo at dir given with --suite, or the default suite.
+func openSuite(dir string) *repo.Repo {
+	r, err := openRepo(dir).Suite(suite)
 	if err != nil {
-		fmt.Println(e)
+		fail(err)
 	}
+	return r
+}
 
-	// Move other debs outside of folder, except old one. Delete that.
-	currentDebs, _ := afutil.CheckDebWithPath(path)
-	for _, c := range currentDebs {
-		if filepath.Base(oldDeb) == c {
-			continue
+// openSuites opens every suite of the repo at dir.
+func openSuites(dir string) []*repo.Repo {
+	var suites []*repo.Repo
+	r := openRepo(dir)
+	for _, name := range r.Suites() {
+		s, err := r.Suite(name)
+		if err != nil {
+			fail(err)
 		}
-		movedDeb := stripedRepo + filepath.Base(c)
-		os.Rename(path+"/"+c, movedDeb)
+		suites = append(suites, s)
 	}
+	return suites
+}
 
-	// Delete and recreate repo. change command to update.
-	os.RemoveAll(path)
-	af.Cmd = "new"
-	af.newRepo()
+// fail reports an error of a repo operation and exits. An update which is not newer is not a failure.
+func fail(err error) {
+	switch e := err.(type) {
+	case *repo.UpToDateError:
+		if e.RepoVersion != "" {
+			log.Println(strings.TrimSuffix(e.Error(), ")") + ", use --force to replace it)")
+		} else {
+			log.Println(e.Error())
+		}
+		os.Exit(0)
+	case *repo.DependencyError:
+		log.Fatalln(e.Error() + ", use --force to publish anyway.")
+	case *repo.UnsignedError:
+		log.Fatalln(e.Error() + ", set signing_key or use --force to publish it unsigned.")
+	}
+	log.Fatalln(err)
 }
 
-// regenerateRepo regenerated the Cydia repo without moving.
-func regenerateRepo(path string) {
+// regenerateRepo regenerates the index files of the Cydia repo r in place,
+// for the debs which are in it now. The debs and the current directory are left untouched.
+func regenerateRepo(r *repo.Repo) error {
 	color.Set(color.FgMagenta, color.Bold)
 	log.Println("regenerating repo...")
 	color.Unset()
-	// Delete and recreate repo.
-	files, _ := ioutil.ReadDir(path)
-	for _, f := range files {
-		if filepath.Ext(f.Name()) != ".deb" {
-			os.Remove(path + "/" + f.Name())
-		}
+	result, err := r.Regenerate()
+	if err != nil {
+		return err
+	}
+
+	// Summarise what changed since the last Packages file.
+	counts := map[string]int{}
+	for _, c := range result.Changes {
+		log.Println(c.String())
+		counts[c.Kind]++
 	}
-	// Create a new repo based on the path.
-	//af := &AftoRepo{Name: path, Cmd: "new"}
-	//af.newRepo()
 	color.Set(color.FgMagenta, color.Bold)
-	log.Println("successfully regenerated repo!")
+	log.Println("successfully regenerated repo! (" + strconv.Itoa(counts[afutil.ChangeAdded]) + " added, " +
+		strconv.Itoa(counts[afutil.ChangeRemoved]) + " removed, " + strconv.Itoa(counts[afutil.ChangeChanged]) + " changed)")
 	color.Unset()
+	return nil
+}
+
+// watchRepo regenerates the Cydia repo r whenever its deb files, in the root or the pool, are created,
+// written, removed or renamed. Events are debounced, so copying several debs regenerates the repo once.
+func watchRepo(r *repo.Repo) {
+	events := make(chan notify.EventInfo, 256)
+	if err := notify.Watch(filepath.Join(r.Dir(), "..."), events, notify.Create, notify.Write, notify.Remove, notify.Rename); err != nil {
+		log.Fatalln(err)
+	}
+	defer notify.Stop(events)
+
+	var pending <-chan time.Time
+	for {
+		select {
+		case ev := <-events:
+			if !watchedDeb(r.Dir(), ev.Path()) {
+				continue
+			}
+			pending = time.After(watchDelay)
+		case <-pending:
+			pending = nil
+			if err := regenerateRepo(r); err != nil {
+				log.Println("unable to regenerate repo: " + err.Error())
+			}
+			// The debs published by regenerating are not changes to watch.
+			drainEvents(events)
+		}
+	}
 }
 
-// runScript executes the dpkg scan packages command.
-func (af *AftoRepo) runScript() ([]byte, error) {
-	cwd, err := os.Getwd()
+// watchedDeb returns whether path, in the repo dir, is a deb the watcher regenerates for.
+// The staging directories and the cache written by regenerating are ignored.
+func watchedDeb(dir string, path string) bool {
+	if filepath.Base(path) == afutil.CacheFile || filepath.Ext(path) != ".deb" {
+		return false
+	}
+	dir, err := filepath.Abs(dir)
 	if err != nil {
-		return nil, err
+		return false
 	}
-	// Restore required assets to current directory.
-	path, _ := filepath.Abs(cwd)
-	dataerr := RestoreAsset(path, ".dpkg-scanpackages")
-	if dataerr != nil {
-		return nil, dataerr
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
 	}
-	txterr := RestoreAsset(path, ".dpkg-gettext.pl")
-	if txterr != nil {
-		return nil, txterr
+	for _, name := range strings.Split(filepath.ToSlash(rel), "/") {
+		if strings.HasPrefix(name, afutil.StagingPrefix) {
+			return false
+		}
 	}
-	// Run ./dpkg-scanpackages -m . /dev/null > Packages and save the output.
-	packages, cmderr := exec.Command("./.dpkg-scanpackages", "-m", ".", "/dev/null").Output()
-	if cmderr != nil {
-		return nil, cmderr
+	return true
+}
+
+// drainEvents discards the events already queued on events.
+func drainEvents(events chan notify.EventInfo) {
+	for {
+		select {
+		case <-events:
+		default:
+			return
+		}
 	}
-	return packages, nil
 }
 
-// executeDpkgScript executes a commandline script which creates a 'Packages' file.
-func (af *AftoRepo) executeDpkgScript() error {
-	output, screrr := af.runScript()
-	if screrr != nil {
-		return screrr
+// listPackages prints a table of the packages entries, or a JSON list of them.
+func listPackages(entries []*deb.Packages, asJSON bool) {
+	summaries := []afutil.PackageSummary{}
+	for _, e := range entries {
+		summaries = append(summaries, afutil.Summarize(e))
 	}
-	file, err := os.Create("Packages")
-	if err != nil {
-		return err
+	if asJSON {
+		out, _ := json.MarshalIndent(summaries, "", "  ")
+		fmt.Println(string(out))
+		return
 	}
-	// Write the Packages file.
-	_, werr := file.Write(output)
-	if werr != nil {
-		return werr
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "Package\tName\tVersion\tArch\tSection\tSize")
+	for _, s := range summaries {
+		fmt.Fprintln(w, s.Package+"\t"+s.Name+"\t"+s.Version+"\t"+s.Architecture+"\t"+s.Section+"\t"+strconv.Itoa(s.Size))
 	}
-	defer file.Close()
-	// Remove unwanted assets.
-	for _, asset := range AssetNames() {
-		os.Remove(asset)
+	w.Flush()
+}
+
+// showPackages prints the full stanzas of the packages entries, or a JSON list of their fields.
+func showPackages(entries []*deb.Packages, asJSON bool) {
+	if asJSON {
+		var fields []map[string]string
+		for _, e := range entries {
+			fields = append(fields, afutil.Fields(e))
+		}
+		out, _ := json.MarshalIndent(fields, "", "  ")
+		fmt.Println(string(out))
+		return
 	}
-	return nil
+	for i, e := range entries {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Print(e.Paragraph().String())
+	}
+}
+
+// indexHTML generates the index.html of a repo listing its debs.
+func indexHTML(debs []string) []byte {
+	var body string
+	for _, d := range debs {
+		body += fmt.Sprintln(`<pre><a href="` + d + `">` + d + `</a></pre>`)
+	}
+	return []byte(header + body + footer)
 }
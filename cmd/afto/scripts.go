@@ -1,7 +1,5 @@
 // Code generated by go-bindata.
 // sources:
-// .dpkg-gettext.pl
-// .dpkg-scanpackages
 // CydiaIcon.png
 // CydiaIcon@2x.png
 // CydiaIcon@3x.png
@@ -73,52 +71,6 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-// _DpkgGettextPl is the .dpkg-gettext.pl script as bytes.
-var _DpkgGettextPl = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x84\x91\x51\x4b\xc3\x30\x10\x80\x9f\x53\xe8\x7f\x38\x99\xb0\xf6\x41\x6b\x05\x5f\x36\x0a\x43\x2d\x43\x10\x15\xf1\x41\x90\x31\xd2\xee\xda\x05\xda\xa4\x4b\x52\x3b\x90\xfe\x77\xd3\xb4\xdd\xc6\x7c\xf0\x25\x34\x77\xdf\x7d\x97\xbb\x4e\x2e\x82\x5a\xc9\x20\x61\x3c\xa8\x50\x16\x70\xd5\xb8\x4e\xad\x10\x94\x96\x2c\xd5\xf3\xfe\xd2\x50\xc9\x19\xcf\x95\xb9\x4e\xe0\x41\x54\x0c\x37\x90\x49\x51\x82\xad\x55\x5b\x2a\xd1\x56\xdf\x05\x8f\x98\xa4\x82\x67\xc1\x12\xb5\xc6\xbd\xbe\xae\x4a\xd7\x39\x13\xba\xce\x7d\xbc\x7c\x7a\x81\x1f\xd7\x21\xf8\x4d\x0b\x98\x76\xe9\x67\x91\xd2\x02\x67\xb3\xbc\x2f\x9c\x1a\x90\xb0\x0c\xbc\xcb\x85\x6f\xc9\x1e\xdd\xd9\x4f\xa2\xea\x04\xd6\x79\x1f\x27\x44\xa2\xae\x25\x07\xb5\x65\x59\xe7\x37\x91\xf6\x40\x75\xae\x8d\x28\x29\xe3\x03\x7d\x4c\xf1\xa1\xd5\xa8\xb1\xdd\xd6\x5f\xb7\x2b\x88\x22\x08\xfd\x31\x3c\xea\x4d\xea\x66\xd5\xeb\x49\x0b\x58\x98\x37\xff\x25\xc2\x03\x71\x6c\xd6\x76\xa1\xd3\x8a\x7f\x27\x19\x1e\xe6\xd9\x89\xfc\xf9\xb9\xca\x75\xda\x71\xa9\x09\x35\xc7\xae\xf1\xe2\x7d\x25\xa4\x46\xd9\xc1\xa2\x96\xb0\x88\x3f\xdf\x5e\xdf\x3f\x22\x93\x32\xf6\x93\x25\x8c\x43\xfb\xf6\x47\x84\xe6\xfc\x0d\x00\x00\xff\xff\x00\x20\x92\xf6\x01\x02\x00\x00")
-
-// DpkgGettextPlBytes returns a bytestring from _DpkgGettextPl.
-func DpkgGettextPlBytes() ([]byte, error) {
-	return bindataRead(
-		_DpkgGettextPl,
-		".dpkg-gettext.pl",
-	)
-}
-
-// DpkgGettextPl holds data from DpkgGettextPl() and returns an BinAsset pointer.
-func DpkgGettextPl() (*BinAsset, error) {
-	bytes, err := DpkgGettextPlBytes()
-	if err != nil {
-		return nil, err
-	}
-
-	info := bindataFileInfo{name: ".dpkg-gettext.pl", size: 513, mode: os.FileMode(420), modTime: time.Unix(1453067566, 0)}
-	a := &BinAsset{bytes: bytes, info: info}
-	return a, nil
-}
-
-// DpkgGettextPl holds data from DpkgGettextPl() and returns an BinAsset pointer.
-var _DpkgScanpackages = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xac\x39\x6d\x73\xdb\x36\xd2\x9f\xcd\x5f\x01\xab\x74\x48\x2a\x94\x64\xa7\x4f\xe7\x99\x93\x2c\xdb\x6d\x92\xb6\x99\x69\x9b\x4e\xd2\xb4\x1f\xa2\x54\xa5\x49\x48\xe2\x99\x22\x59\x02\xb4\xeb\xb3\xd5\xdf\x7e\xbb\x0b\x80\x04\x69\x39\x97\xcc\x9d\x66\xa2\x08\xd8\xc5\xbe\xbf\x01\xfe\xe2\x70\x52\x8b\x6a\x72\x99\xe6\x93\x92\x57\x99\xe3\xd4\x82\xb3\x9b\xa8\xca\xd3\x7c\x2d\x66\xb4\x12\xb2\x4a\x63\x39\x53\xa0\x57\xaf\xa7\xd3\xef\xa3\x3c\xc9\xf8\xac\x59\x7f\x9b\xe2\xca\xd9\xde\x32\xf7\x9a\x57\x22\x2d\xf2\xf9\xe0\x64\x7c\xf2\xe5\xf8\xd9\x57\x83\x19\xfb\x82\xfd\xb2\x49\x05\xcb\xd2\x9c\xb3\x6d\x91\xa4\xab\x94\x27\xec\xf2\x96\xfd\x18\x5d\xf1\x15\x9c\xa4\x73\x49\x79\xb5\xce\xd2\xcb\x24\xad\xe6\x03\x12\x08\x16\x13\xdc\xfc\x14\x02\x8e\xef\x1e\x07\x6c\xce\xdc\x63\x36\xff\x9b\x6d\xa7\xe3\xe1\xc4\x1f\x3f\x0d\xa6\x20\x53\x59\x8b\x8d\x7f\xf1\xea\xa7\xe7\xa1\xc5\x22\x98\x39\x15\xff\xb3\x4e\x2b\xce\xbc\x31\x6e\x8f\xd6\x5c\x4a\xfe\x97\x1c\x97\x99\x37\x73\xf0\x57\x52\x6c\xa3\x34\xf7\x07\x04\x4d\xf8\xf5\x20\x50\x0a\x1e\x5d\x6d\xa3\x72\xce\xfc\xa2\x94\xa0\x66\x94\x31\xf3\x99\x9f\x31\x4f\xd4\xeb\x35\x17\x52\x78\xa1\x73\x00\x5b\x15\x8f\x8b\xed\x96\xe7\x09\x88\xdb\xe0\x34\x9b\x06\x2b\xce\x22\x21\x98\xf5\x41\xac\xb2\x4a\x8b\x2a\x95\xb7\x1a\xa7\x8c\xe2\xab\x68\xcd\x97\x15\xbf\x4e\xd1\xbc\x9a\x92\x5a\x28\x1c\x2d\xdf\x05\x18\x27\x4b\xe0\x38\xc8\xe8\xfd\xac\x8e\x69\x22\xf8\xf1\xde\x16\x75\x15\x77\x76\x7e\x55\x1e\xb3\xb7\x7e\xee\x72\x57\x07\x79\x2c\x7b\x68\x2f\x85\xe0\xb9\x4c\xa3\xcc\xde\xfc\x11\xcc\x26\xe1\x1f\xaf\xba\x14\xf9\xe8\x05\x2f\x2d\xb5\x69\x7b\xcf\xd6\x9b\xbe\x7d\x14\xf7\xae\x65\x69\xef\x79\x91\xaf\x32\x08\x4c\xd1\x65\x54\x5c\xa7\x09\xef\x91\x2c\xb3\x28\xee\xee\xbd\xcc\x37\x51\xde\xdb\xfb\xba\x8a\x37\xa9\x04\x3d\xeb\xaa\x63\x21\x0c\xef\x3c\xda\x76\xed\x98\xfe\xab\xb3\x7e\x95\x0b\x19\x65\x19\x4f\x46\x7d\xc8\x8f\x2f\xbe\x12\xf5\xb6\xab\xb5\x88\xab\xb4\xec\x9b\xf3\x75\x95\xae\xd3\xce\xce\x37\xf5\x1a\x05\x64\xdd\x8f\xf7\x93\x92\xa5\xbf\xfd\x75\x2d\x37\x45\xb5\x07\xf0\x7d\xb1\xe5\xa5\x8a\x83\x3e\xe8\x37\x7e\x29\x40\xe5\x3d\x10\x70\x4d\x6a\x3c\xde\x87\xbd\x8a\x61\xdb\x88\x89\x81\xa7\x33\x14\x52\x43\x30\xb9\xe1\x8c\x82\x50\x30\x88\x84\x82\xd6\x65\x55\x40\x71\x61\x71\x24\x28\xdb\x8f\x08\xbe\xc4\xe5\xcc\xb9\x68\x17\x77\x40\xe0\x2e\x8b\x7d\x77\x19\xec\xda\x48\xde\x41\x66\x37\x0b\x5d\x85\xbe\xe3\x12\x32\x70\x3a\xfd\xa1\xc8\xd7\xec\xcf\x1b\x7f\x0a\x12\xad\xd2\x35\xbb\xac\xa1\x32\x41\xe5\x32\xd9\xaa\xd2\x54\x00\x05\x7f\xc3\xb3\xd2\xd6\x02\x52\x48\xd4\x97\xec\x8e\x3d\xa9\x05\x18\x67\xc6\xf8\x5f\xa9\x64\xc7\x33\xb6\x6b\x3d\xa0\xab\x99\x7d\x66\xf1\x44\x6f\xb6\x58\x75\xc2\x2f\x7b\x94\x8f\x5b\x68\x04\x51\xd5\x83\x82\x90\x7c\xd5\x62\x6c\xeb\x4c\xa6\x16\xab\xce\x79\xad\x89\x5b\x71\x01\x68\xa0\x08\xa8\xfe\x5a\x69\xe5\x2f\x8c\x7e\xa1\x87\xda\xdd\x6f\xee\xcf\xbd\xd0\xbb\x36\x09\xed\xa1\x60\xf7\xf5\x21\xfc\x42\x21\xee\xa3\x39\x44\x93\x67\x73\xbb\xdf\x1e\x7a\xc8\x00\x0d\x61\x04\xb8\x23\x77\x83\xa9\x73\xb9\x62\xcb\xb5\x3f\x78\xc1\x2f\xd3\x28\x67\x47\xa2\x41\x39\x12\xe3\x45\x3e\x08\x42\x28\xb8\x61\x53\xf2\x67\x74\x0e\x8d\x38\x73\x76\x8a\x24\x19\xf6\x01\x41\x67\xf0\x0e\xf7\xa7\x48\xf1\xfd\xa9\xd2\xe0\x8c\x8d\xc7\xe3\x0f\xec\x14\xda\x50\x54\xdd\x96\x91\xdc\x9c\xb1\xd3\x02\x28\x57\x90\xcc\x58\xe3\xcf\x00\x15\xb7\xcb\x0a\x96\x7f\x9d\x7d\x60\x67\x4c\x57\x37\xe1\x38\xda\x1e\x53\x60\x34\xaa\x43\x36\x1a\xf5\x3d\x02\x1f\x11\x83\x0e\xab\xa2\x22\x6f\x89\x31\xa2\x46\x88\x4a\xee\x39\xc5\xef\x33\x66\x79\xcc\xd4\x01\x06\x11\x6c\x8e\xd2\xa1\x2d\x1e\xea\x7b\x0c\x0f\x65\x59\x71\xa3\x5c\x59\x66\xdc\x98\x4a\xb0\x62\xc5\x22\x26\x20\x24\x61\x53\x97\x71\xa2\xb3\x41\x3a\xfd\x98\x44\x39\x37\x40\x46\x62\x36\x11\x70\xcb\x85\xd0\x47\xf0\x33\x1a\xf5\x43\xb2\x3d\xd2\x30\x1d\x3b\xca\x37\xe4\x07\x15\xdd\x0c\x3a\xb6\x0a\xf0\x13\x96\xae\x58\x5e\xc8\x26\xa2\x10\xe2\x5f\x7c\xfd\xe6\xbb\x5f\xd9\x29\x7b\x66\xc2\x8d\x2c\x08\x1d\x55\x07\xd8\x1d\xae\x77\x33\x02\x91\xc5\x2c\x10\xae\x77\xfa\x18\x74\xce\x07\xa7\xd8\x39\xa3\x48\xf4\xd8\x94\x79\xf8\xff\x4c\x37\xaa\x3c\x59\x46\x15\x0e\x19\x20\x91\xdf\x25\x17\xe8\xa8\xb1\xd0\x30\x87\x3d\x1f\x02\x78\xa4\x6a\xf1\x60\xb8\x04\x9b\x8f\x91\xe5\x00\x36\x8b\x0e\xc4\x55\x64\x0c\x34\xf0\xc2\x00\xad\xc1\x33\xc1\x1f\xa1\xdc\x9c\x55\x67\x08\x1d\x87\x07\x0b\x2f\x64\xde\x68\x55\xa0\x9f\x95\x0a\xbe\xab\xc2\x15\x26\x0a\x30\xb7\x89\x56\xf8\xd9\x06\x2a\x4e\x25\x64\xdb\x99\x33\x4a\x58\x8b\xcf\x20\x10\x93\x14\x46\x2b\x95\x16\x3e\xe6\xd9\x37\x04\x64\x08\x85\xcc\x40\x17\xad\x0a\xa8\x14\xe0\xcb\x7e\x01\xee\x7e\x5a\xaa\xc1\x78\x00\x79\x09\xac\x78\x2b\xce\x3e\x4e\xaf\x0d\x0c\x33\xeb\xb3\x98\x35\x64\x0d\x2b\xc7\x52\x16\x74\xf5\x3c\x13\x5e\x98\xb6\x39\x0c\x3e\x16\x1c\xb0\x61\xfa\x60\x47\x40\x22\x8e\xe2\x0d\xd4\x7e\x5d\x77\xe2\x6d\x69\xbc\x42\x66\x8d\x42\xf7\x32\x98\x5f\x2c\x55\x49\x81\x39\x0a\x32\x31\xa7\x42\x43\xe7\xee\xdc\x68\x77\xe7\x42\x60\x01\x2b\x88\x69\x18\x0b\x1e\xc2\xf4\x51\x71\x2b\x24\xdf\xfa\x1e\x8e\x71\x18\x20\x23\x18\x2e\xca\x08\x86\x11\x93\x9f\x5e\x08\xdc\xbc\x0c\x3c\x0f\x2c\xf5\xa1\x07\xc4\xe6\xee\x79\x4f\x94\x73\xca\x2d\xec\x30\x3a\xa7\x85\xca\x0d\x8a\x15\xcc\x8e\x9c\xdf\x74\x46\x65\xe8\x7f\xb9\xaf\xc1\x20\xc7\x3d\x08\x83\x0b\x08\xb8\xd6\x7d\x93\x41\x68\x05\x9b\x37\x22\x8f\x79\x81\xe2\xbc\xc7\x8d\xcf\x8b\x3a\x4b\x72\x4f\x32\x24\x8e\x6e\xc4\xf2\x56\xf1\x28\x81\x6a\x83\xd5\xf5\x31\x67\x76\xc2\xf6\xd0\x78\xf2\xdb\x57\x3f\xbc\x9c\x12\xfe\xcd\x06\xc3\xc2\x3f\xd5\xe2\x9e\x61\x32\x1e\xc4\x1b\xb0\xdc\xcc\x39\x20\x2d\x73\x4c\xf2\xa5\x5e\x41\xeb\x95\x55\x91\xc1\xd6\x1f\x7a\x5a\xbe\x64\xa3\x57\x84\xa5\x41\x7f\x00\x26\xe5\xb8\x41\xe5\x7f\xb2\xc1\x80\xc8\x12\x3f\xb8\x6d\xec\x57\x2c\x86\x0c\x67\x0d\x51\xea\x3c\xa8\x58\xc8\xc4\x55\x5a\x96\xa0\xa6\x29\xa9\x54\xf0\x56\xb9\xad\x0f\x91\xce\x21\x97\xe1\xe7\x4e\xf3\x3f\x7f\x9c\xe7\xa2\x23\x3c\x18\x53\xcb\xea\x51\xd9\x84\x40\xbe\x49\xe5\x86\x1d\x25\x1f\xe3\x7d\xfe\x08\x6f\xb2\xd3\x91\xbc\xc6\x42\x13\x68\xab\x41\x58\x96\x68\x45\xcd\x06\x76\xb5\xd9\x35\xe4\x6f\x26\x26\xbf\x2f\xf2\xa1\xbf\x78\x0b\x37\x99\xf7\x6c\x21\x3f\x0c\xfd\x31\x2c\x73\xfa\x3d\x1e\x06\xc3\x60\x91\x4f\x26\x8d\x46\x94\x39\x57\xfc\x36\x74\xaf\xa3\xac\xe6\x01\xf0\xca\x62\xe6\x9e\x84\x2e\x16\x75\x42\x41\x1b\xe8\xbc\x04\x54\x1c\xb6\xf0\xc0\x2e\x00\x1a\x0c\x7f\x81\x38\xed\x2e\x0c\x42\x7b\x4e\x59\xc3\xda\x83\xb3\x7d\x58\x43\x41\x49\xa4\x74\x5a\x88\xa7\xee\x64\xa2\x05\x72\xe5\xb5\x42\x9d\x6b\x1c\x65\xaf\xc6\x04\x64\x01\x77\xa2\x90\xf7\x64\xc0\xbb\x1c\x26\x4b\x98\xe2\x05\xf8\x07\x6f\x6d\x6c\x55\x15\x5b\xcb\x79\x54\xe0\x66\x30\x87\xae\x8a\xe9\x22\x87\xfd\x09\x00\xf5\xe8\x42\x1e\xd3\x78\xa1\xf2\x07\xda\xc9\x39\x68\x74\x05\xe1\x9a\x2b\xd4\x2e\x78\x54\x88\x9f\x0a\x33\x8a\xa8\xb9\x17\xd8\x75\xf8\xe3\x08\x40\xc9\x88\x3c\x9b\x08\xc1\x18\x80\x8b\x64\x97\xc9\x0c\xca\x66\xc6\x25\xef\x6f\xa3\x58\x1d\x37\x98\xaa\x73\xe7\x96\xd0\x2b\xb1\x85\x53\x4b\x37\x3d\xd4\x9e\x4f\x76\x4d\x84\x40\x79\xe0\x50\xd6\xa0\xdb\xdf\x75\x08\x28\x0c\x62\xf0\x44\x55\x63\xa5\xbb\xb9\x19\xee\x40\xf2\xe5\xe8\xcc\xda\x08\xd4\x09\x24\xaa\x0d\xf1\xf6\x97\x17\x2f\xdf\xbc\xa1\xf1\xee\x00\x01\x03\x76\xd8\x58\x05\xec\xee\xaf\xf4\x1d\x0a\x16\x01\x83\xe1\xa6\x82\x9b\x5f\x24\x61\x5e\x97\x58\x2a\xe1\x6e\x60\xa6\x48\xb0\xce\xd8\x90\x80\xd1\x9a\x3c\xbb\x01\xcc\x22\x57\x43\x4c\xba\xce\x41\x8d\x84\x25\x91\x8c\x1a\x77\x1f\x6a\x9f\x96\xc6\xaf\x28\xae\xb9\xb6\xa1\xeb\x0e\x0e\xee\xef\xc9\x71\xee\xe1\x4c\x0b\xde\x31\x01\x64\xe2\xfb\x0f\x08\xd9\x31\x3d\x23\xfc\x0f\xb4\xeb\xe9\x62\x24\xef\xa8\x53\xe3\x68\xf8\xd9\xca\xe8\x28\x6c\x95\xc1\x5a\xc3\xb0\x82\x93\x0e\xca\xdd\x3b\xca\xa5\x07\x1a\x7c\x54\xf6\x4d\x24\x98\x61\xa5\xa2\xb9\x2b\x4d\xc0\x1a\x3b\x36\xc5\xa1\x93\x2f\xcd\x5d\x79\xa7\x92\xa9\xb7\x39\x67\x03\x6b\x20\x00\x82\x03\xc2\xa2\xee\xf8\x3c\x1c\x6c\x93\xaf\xd8\x29\x36\x8e\xfb\x41\xc3\x69\x80\x6b\xf7\x10\x11\xa1\x07\x41\x68\x2e\xe7\xa7\xcf\xcf\x82\x19\x8b\xb3\x42\x70\xff\x39\xfc\x72\xcf\xc9\x96\xfd\xc4\x5c\xfc\x41\xf4\x40\xb1\x7e\x11\xdf\x57\xb2\x27\xbf\xfb\xef\x8f\x47\xff\x88\x46\xab\x0f\x77\x5f\x3e\xdb\x05\x0b\x31\x1c\x9d\xc3\x97\x3b\xd9\x97\xf4\x6f\x65\x15\xe5\x60\xc1\xb6\xea\x58\xdc\xa6\xb0\x80\xff\x5a\x2e\xcb\x86\x0b\xd9\x43\x3f\x09\x60\xd5\x3b\x99\xe9\xf6\x70\x21\x64\x24\xe7\x0c\xbf\x7d\x32\xf4\xc7\x1a\x3e\x62\xe9\x5e\xb8\xaf\xf3\xb9\x08\x7f\xff\xff\x1f\xf6\xd1\x00\x97\x43\x80\x42\xb1\x93\xb7\xfd\x92\x44\xb2\xd1\xf3\x05\x4a\xa6\x69\xf4\x6b\x0f\x95\xa6\x37\xfa\xe9\x69\x47\x76\x07\xe7\xae\xe5\xc6\xef\x00\x82\xa6\xea\xe0\xb6\x2e\x1c\xbb\x31\x4c\x88\x23\x6f\xdc\xc1\xd4\xad\xc0\x2a\x7c\x36\x88\xba\xa7\x9a\xc3\x7b\x45\x2b\x64\x77\xd0\x52\xf5\xa0\xb7\x73\x54\x34\xe8\x91\x85\xee\xb4\x40\x30\x96\x3a\xf0\x31\x48\xe6\x90\xe4\x27\xc7\xc7\x70\xc3\x6f\x41\xaf\xdf\xfd\x62\xee\xbf\x37\x15\x44\x48\x06\xd3\xa4\x1e\x45\xa9\x49\xa7\x32\xe3\xe0\x94\x4d\xba\x92\xfe\xc5\x52\x0f\x88\x7a\x08\xac\xf3\x0c\x3a\x0f\xc3\x31\xb5\xbd\xe1\x9a\x44\x1b\xe8\xc3\x98\x3d\xcc\xaa\x3d\x86\xb2\x51\x65\xce\xfe\x59\xa4\xb9\xef\x31\x2f\x14\x45\x25\x19\x31\x71\xa0\x52\x6f\xc1\xc3\x9a\xd6\x1c\x4e\xfd\x7e\xfa\xdf\x7f\x9c\x86\xab\x33\xb6\x07\x3d\xe3\x3f\x03\xa5\x9e\x4e\xe6\x30\xd6\xb3\x34\x00\x53\x3f\x54\xb6\xaf\xa5\x9a\x8d\xfd\x0b\x01\x49\x8f\xef\xa8\x32\xbc\x88\x37\x98\x2f\x09\xad\x50\x45\xf5\x3c\xa3\x6e\x13\x09\xcf\xd5\xf8\x6c\x6e\x17\xcb\x95\x3d\x43\x63\xf9\xb0\xee\x57\x5e\xe5\x39\x9f\x34\x11\x17\xbd\x6b\x4e\x93\x2e\xd6\x55\xad\x49\x9a\x66\xe8\xb5\x64\x38\x33\xd7\x50\x18\x5b\xbe\x18\x0f\x71\x6c\xd1\x2b\x3d\xc4\x34\xa5\x57\xc7\x82\xbb\x6c\x3c\x0c\xe6\x0c\x5d\xf3\x8c\x1b\xba\x42\x3d\x9f\x86\xee\xb6\x79\x1f\x85\x09\x4d\x94\x59\x2a\x7d\x24\x37\x09\xdd\x65\xf8\x7f\xc1\x43\x92\xfb\x5b\xbe\xc2\xc3\x79\xdf\x0a\xa6\x47\x1a\x3b\x0d\x70\x36\x5f\x93\x99\xac\x0f\xa1\xd7\x73\x7c\x3a\x3f\xc7\xea\x37\x5f\x9c\xc1\x37\x3e\xa4\x4f\x9a\x76\x4f\x4e\xca\x94\x17\x75\xf5\x32\xdb\xe0\x2d\xb3\xfd\xcc\xda\x86\x81\xda\x6c\x1b\xd9\xee\xda\x37\xe2\x9d\xc6\x44\x41\x0e\xd7\xd0\x36\xfd\xe6\x00\x5e\x12\xc0\x26\xda\x46\xdb\x29\xc8\x32\x01\x4b\x0d\xa7\x61\x2b\x42\xa0\x07\x91\x03\xf5\xcc\x6f\xc7\x58\xa8\x1a\x2f\xa3\xde\xeb\x96\xcc\x37\x46\x12\xd1\xad\xd8\x2f\x4b\xa8\x87\x28\x43\x1c\xa3\x5a\xcb\x67\x8d\x05\x07\x7b\xcf\xce\x5b\x03\x98\x23\xcd\xfb\x1e\x1d\x26\x53\xef\x3b\x49\x7a\xf6\xbc\x43\x6f\xfe\xa4\x52\x9b\x41\x5a\x0d\x1b\x55\x0b\x68\xb3\x69\x46\x97\x3d\x7d\x7f\xc8\xde\xe5\x30\x98\x26\xa9\xfe\xd3\x85\xe5\xf8\x36\x55\x0a\x7a\x7d\x18\x0e\x54\x5e\x60\xe3\xef\x64\x36\xd1\x7e\xcc\x02\x2d\x41\x4b\x2a\xfc\x85\x5f\xed\x21\xf3\xe7\x05\x3c\x62\x32\x64\xd6\xc1\xd0\x7f\x6c\xa0\x36\xa4\x7e\x9a\x32\x4f\x1d\xa5\x2d\x1b\x7a\x76\x3b\xc1\x92\xa3\x3b\x80\x95\xbf\xe6\x6f\x22\xdb\x54\xe0\x98\x85\x90\xb9\xdf\x3e\xa8\xc6\x45\x95\x88\x25\x16\x3a\xc9\xf1\x46\x7b\x3c\x73\x9a\x8c\x62\x3e\x95\x63\xb8\x9b\x88\xf6\x8e\x6f\xca\x01\xfa\xd2\x7a\xe3\xf0\x7b\x02\x05\xcd\xeb\x55\xe3\x46\x4b\x02\xa8\x0a\x81\xad\xcd\x27\x26\x71\x2b\x32\xcc\x50\x7a\x7e\x9b\xc2\x29\x7d\xdf\x34\x54\x40\x60\xa0\x60\x5e\xcb\xad\x54\xdf\x53\x53\x2c\x83\xab\xfb\x9a\xc1\xd5\x8c\xd8\x18\xc7\x35\x80\x4c\xfb\x98\x9a\x29\xb9\xd5\xc2\xd5\xdb\x7d\xd3\x3e\x7d\x8a\xbb\x4d\xc7\x80\x9e\x6b\x50\xf6\x4e\x39\xdf\x46\x50\x89\x61\x54\xdb\x80\x53\x90\x02\x0e\xc8\x42\x26\x45\x2d\x9b\x02\xde\x54\x6d\x65\x46\xe3\x7b\xdd\xd0\x3f\xda\x19\x08\xf3\x51\x7a\x14\x2e\xa2\xac\x21\x2a\x6b\x41\xf1\xc2\xa8\x2a\x1d\xee\x29\xc3\xcb\x5d\x10\x5a\x41\xd2\xc6\x00\x46\xd8\x93\x66\x9c\x20\xf6\xc3\x61\xf3\x5c\x8d\xd7\x43\x7a\x61\xbe\xe6\x74\xfd\xd1\xa1\xa1\x86\xc8\x4e\xcb\x9a\xb2\xe1\xb0\xf7\x86\x63\x07\x52\xa0\x1e\x4e\xbb\x9d\xd5\x7e\x6d\xd7\xc9\x6f\x9d\xa7\x32\xf0\xf4\x69\x47\x96\x6e\x97\xa4\x01\x39\x85\x32\x51\x81\x87\xa0\x8f\xc2\x75\xd6\x2a\x13\x74\x47\x9f\x02\x05\x53\x1e\x2c\xda\xbd\xea\xdb\x02\xf6\x4c\x07\x24\x77\x53\xda\x3e\x45\xe8\xd1\xa8\x15\x5a\x94\x3c\x4e\x57\xb7\x14\x17\x78\x4f\xb1\x04\x84\xeb\x4b\xcf\x84\xa3\xd1\x3e\x59\xdb\xb2\xfa\x29\x82\x5a\xf1\xf0\xb9\xb2\x3e\x30\x30\xdd\x78\xa1\x76\xb4\x51\xf0\xa8\x8c\x16\xdb\xff\x20\xa6\xb3\xa7\xda\xff\x56\x15\x92\x6e\x78\x3c\x97\x55\x0a\xa2\xc8\x82\x41\xcc\x97\xc0\xbf\x91\x0e\x25\x1a\xb7\xa5\xbe\x97\xb7\x1d\x26\xff\x0e\x00\x00\xff\xff\xc0\x65\x1b\xf4\x28\x20\x00\x00")
-
-// DpkgScanpackagesBytes returns a bytestring from _DpkgScanpackages.
-func DpkgScanpackagesBytes() ([]byte, error) {
-	return bindataRead(
-		_DpkgScanpackages,
-		".dpkg-scanpackages",
-	)
-}
-
-// DpkgScanpackages holds data from DpkgScanpackagesBytes() and returns an BinAsset pointer.
-func DpkgScanpackages() (*BinAsset, error) {
-	bytes, err := DpkgScanpackagesBytes()
-	if err != nil {
-		return nil, err
-	}
-
-	info := bindataFileInfo{name: ".dpkg-scanpackages", size: 8231, mode: os.FileMode(493), modTime: time.Unix(1453067566, 0)}
-	a := &BinAsset{bytes: bytes, info: info}
-	return a, nil
-}
-
 var _cydiaiconPng = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x00\x47\x15\xb8\xea\x89\x50\x4e\x47\x0d\x0a\x1a\x0a\x00\x00\x00\x0d\x49\x48\x44\x52\x00\x00\x00\x3c\x00\x00\x00\x3c\x08\x06\x00\x00\x00\x3a\xfc\xd9\x72\x00\x00\x00\x01\x73\x52\x47\x42\x00\xae\xce\x1c\xe9\x00\x00\x15\x01\x49\x44\x41\x54\x68\x05\xed\x5a\x07\x78\x55\x65\x9a\x7e\xef\xbd\xe7\xe6\x96\x74\x02\x09\x1d\x42\x11\xa4\x8b\xa2\x32\xa2\x80\x3a\x63\x81\x60\x47\x64\x15\xb1\xce\x38\x76\x1d\x65\x56\x67\x5c\xd4\x5d\xcb\x58\x57\xd7\x01\xc7\x82\xa2\x8c\x8a\x6b\xa1\x2a\x3a\x8a\x28\x3a\x20\x01\x04\xa4\x97\x24\x10\xd2\x7b\xb9\xfd\x9e\x33\xef\xf7\x9f\xdb\x12\x12\x0c\xc8\xec\x3e\xbb\x0f\x7f\x9e\x9b\x7b\xce\xf9\xdb\xf7\x7e\xfd\xfb\xcf\x05\x8e\xb7\xe3\x1c\x38\xce\x81\xe3\x1c\xf8\x3f\xc4\x01\xcb\x3f\x83\x56\xc3\x80\xa5\xe8\x53\x5c\x60\xb5\xe0\x32\xe8\x18\xa2\xeb\xb0\xdb\xac\x28\xe5\xf3\x2f\x83\x3a\x96\xf6\xcf\xc3\xee\x7f\xc6\xbe\x1d\x59\xf3\x98\x03\xde\xf3\x31\x7a\x25\x39\xf0\x12\xc1\xe6\xd9\xb8\xba\x0e\x0b\x0c\xe1\x00\xa9\xb1\xf2\xe3\x0f\xa2\x89\x17\xab\xc9\x80\xff\xb6\x18\x58\xd1\xf3\x42\x14\x77\x84\xd0\x63\x35\xe6\x98\x02\xde\xb7\x0c\x7d\x34\x2b\x16\x27\xd9\x30\xc2\x17\x72\x21\xbd\xff\x2c\xb8\x72\x26\xc3\x08\x7b\x10\xf2\x14\x21\xd0\xf0\x03\x02\x75\xdf\x01\xde\x8d\x30\x42\x3e\x04\x83\xa8\x86\x0d\x2b\x2d\x16\x7c\x90\x64\xc5\x97\x5d\xcf\x43\xc5\xb1\x02\xd6\xde\x3a\xc7\x0c\x70\xe1\x62\xe4\x5a\x35\x2c\x22\xe0\xe1\x21\xad\x2f\x3a\x8f\x7a\x1d\xae\xac\x89\x87\xec\x2b\xd2\x0e\x36\x6d\x83\xb7\xe2\x13\xf8\x2a\x17\x01\xcd\xdf\x23\x1c\x08\x80\xaa\x5e\x66\xb1\xe2\x0b\x76\x7f\x64\x23\x13\x7a\x9d\x8f\x9a\x43\x26\x1f\x83\x07\xc7\x04\xf0\xee\xe5\xe8\xef\x00\x3e\x26\xd8\x61\xba\x6b\x24\xb2\x4f\x7e\x1f\xf6\xe4\x81\x4a\x95\x0f\xa5\xd1\x02\x4a\x54\x35\x01\x1f\x68\xd8\x04\x6f\xf9\x12\xf8\x2b\x3f\x86\xe1\xd9\x00\xda\x3b\x42\x61\x14\x1b\xc0\xe7\x30\xf0\x81\x2f\x8c\xaf\x07\x5f\x84\xc6\x43\xd7\x39\xba\x27\x3f\x1b\xf0\xce\x25\x18\xec\xd6\x28\x15\x60\xb0\xee\x1e\x85\x9c\x31\x8b\xa1\xb9\x7a\xb5\x03\xb6\x35\x91\x09\xe0\xf5\x20\xbc\x55\x9f\xa3\x7a\xcb\x6f\x80\xc0\x01\x24\xd9\x15\x70\x61\xc0\x6e\x32\x68\x91\xa1\xe3\xbd\x5e\x93\xb0\x9e\x04\x93\x17\x47\xdf\x7e\x16\xe0\x7d\x8b\x30\x48\x4b\xc2\x52\x82\x1d\x60\xb8\x86\x13\xec\x12\x68\xee\x3e\x1d\x04\xdb\x92\x68\x0b\x51\xe9\xb4\xf5\x92\xaf\x47\x22\xd8\xb8\x07\xe2\xe5\x44\x13\xe8\xdc\x60\xd7\xe8\xec\x42\x08\xf2\x72\x8d\xd5\x8a\xb7\x69\x01\x8b\xfa\x5d\x84\xf2\x96\x2b\x74\xec\xee\xa8\x01\x1f\x58\x82\x1e\xba\x0d\x2b\xec\x36\x0c\xd5\x9d\x54\xe3\x53\x3e\x82\xdd\x9d\xdb\x0a\x6c\x74\xf9\x9f\x12\x8a\x48\xda\x40\xe5\x0f\x33\xd1\x58\x38\x1f\xee\x6e\xbf\x42\x6a\xef\x9b\x61\xb5\xa7\x23\xec\x2b\x81\xbf\x3e\x1f\x81\xda\xd5\x74\x76\x3f\xd2\xed\x07\x11\x0c\xa1\x84\x9e\xfe\x03\x86\x80\x37\xfb\x4c\xc6\xfa\x8e\x41\x35\x47\x45\x29\x3a\x92\x39\x28\x59\x02\x77\xc0\x82\xa5\xce\x24\x4c\x0c\xa0\x1b\xba\x9d\xb9\x1e\x9a\xb3\xdb\x21\x6b\xd0\x44\x55\x8b\xdb\xac\xdc\xb6\x06\x6f\xaa\x75\xed\xce\x87\x50\xbb\xe3\x51\x74\x3a\x71\x36\xd2\x07\xfe\x81\x0c\xa0\xde\x24\x34\x83\x40\x03\x8d\x9b\xe1\x29\x5b\x44\x7b\xff\x08\x16\x82\xf7\xf9\x11\xb0\xda\xf0\xa9\x6e\xc1\xcb\x05\x6b\xf1\xd9\xc4\xd9\x08\x25\x4c\x69\xf3\xf2\xa8\x00\xef\x5b\x82\x67\x92\x5d\xb8\xc7\xe3\xa3\xda\x59\x5d\x48\xe9\x75\x03\x92\xbb\x5f\x09\x5b\x52\x36\x3d\x6e\x05\xfc\x75\x6b\xe1\xaf\xf9\x0e\x21\xdf\x7e\x6e\x6a\x81\x3d\x65\x08\x52\x7a\x4c\x87\xb3\xcb\xaf\x22\x78\xe3\xa0\x45\x95\xeb\xf7\xfe\x09\x35\x5b\x67\xa1\xd3\xb0\x67\x90\xde\xef\x9e\x18\x4b\xda\x23\x4e\x0f\x7b\xe1\xab\xfe\x0a\xcd\x07\x5e\x45\xb8\x6e\x39\x82\x3e\x12\x62\xc5\xb7\x64\xf0\x0b\x6b\x9b\xf1\xc1\xd4\xa9\x08\xb7\x89\x56\x51\xd3\x5e\x4f\x1b\xcf\xf3\x17\x22\x3d\x3b\x05\xf7\x91\xa2\x7f\x0d\x87\x61\x15\x09\x32\x94\x80\x0e\x05\x22\x10\x8b\xd5\xc9\x6b\x1f\xe3\xae\xb9\xb2\xd5\xee\x52\x17\x7a\xd0\xa3\x80\xa6\x0f\xb8\x13\x9d\x86\x3c\x1f\x53\x7b\x01\xdb\x78\xe0\x0d\x54\xfd\x70\x3d\x32\x4f\x7c\x1c\x19\x03\x66\xa9\x5d\xf5\xb0\x1f\x7a\xa0\x12\x61\x7f\x39\x35\x98\xd1\x89\x9b\xd8\x9c\x3d\x68\x32\x03\x78\xa9\x29\x86\x44\x99\xe1\xaf\xdf\x40\x33\x78\x01\xc1\x8a\x77\xe9\x03\x38\x0f\xf8\x8c\xc9\xcd\x3d\x03\xa7\x60\x6b\x1b\x10\x54\x02\xd4\xd6\xf3\x43\x9e\x15\x7d\x82\x73\x68\x66\xcf\xd2\x66\x47\x84\xed\xb9\xb4\xb3\x69\x8a\x88\xfa\x3d\x8f\xd0\xce\x2a\x08\x82\x53\xa2\x82\xe3\x77\x72\xaf\x2b\x90\x35\x6c\x0e\x1f\x5a\x10\x6c\xde\x49\xef\xfb\x5b\xf8\x6b\x7f\x40\x97\x51\x73\x90\xda\x87\x9e\x98\xcd\x53\xbe\x18\x95\xeb\xa7\x71\x48\x18\x19\x83\x1e\x27\xb8\x2a\x86\xa9\x1f\x11\x6a\xde\x4d\xed\x38\xc0\xfb\x66\x93\x99\x44\x67\xd1\xec\x48\x4a\x1b\x8d\xb4\xdc\xdb\xa9\x4d\xd3\xf9\x80\x0f\xb9\xa9\x30\x4d\x9a\xbf\x76\x2d\x6a\x77\x3d\x04\xd4\x7f\x86\xb0\x81\x72\x5f\x00\xd3\x07\x4c\xc1\x97\xaa\x33\xe1\x5f\x94\x51\x09\x8f\x5a\x5e\x1a\x0b\x61\x2b\x4a\xc1\x03\x56\x03\x7f\xb0\x69\x49\x49\xce\x9e\x77\x30\x83\xba\x1f\x36\x47\x17\xf8\x99\x39\x95\xaf\x39\x8f\x92\xa8\x80\x3b\xe7\x7c\x24\xf7\xb8\x86\x99\xd4\x3a\x02\x59\x8a\x30\x89\x77\x67\x4f\xa6\xba\x5f\x0f\x57\xe7\x89\xbc\xaf\x47\xc9\xaa\xa1\x94\x5c\x2d\xc1\xfd\x1b\xa5\xd1\x8c\x86\xbd\x4f\xab\x2c\x4c\x98\x65\xd0\xfa\x84\x5f\xa2\x31\xb6\xa4\x24\xd8\x5c\xbd\xf9\x9d\xc3\x7b\x3b\xc7\xf8\x10\xf2\x16\x31\x5b\x2b\x55\x83\x52\xfa\x5c\xa3\x98\x69\xd1\x92\x15\x68\xa1\x58\x80\x8b\x9d\x57\x6f\xf9\x35\xfc\x25\xf3\x44\xd2\x95\xd4\xc2\xb3\x72\xf3\xb0\x23\x11\xd1\x61\x01\x33\xa1\x48\x63\x38\x7c\xd9\x61\xc3\x34\x3d\x69\x20\x32\x87\xbe\x04\x57\x97\x5f\xaa\xf9\x21\x7a\xcf\xd2\x6f\x4f\xa3\x34\x8a\x91\x31\xf8\x21\x64\x9e\xf0\x70\x6c\x5d\x51\x2d\x49\x21\xab\x36\xdd\xcc\xac\x6a\x0f\x6d\x77\x1c\x1c\x99\x67\xa0\xa9\x68\xae\x02\x2e\xd4\x48\x13\x70\xd2\x5c\xd9\xe7\xc3\xd9\x69\x3c\xac\x8e\x6c\x26\x2c\x83\x19\xda\x72\xc9\xd0\x6e\x4a\x88\xe6\x08\x3a\xe7\x50\xa3\x8a\xd3\x35\x5b\x6e\xa3\xc6\x94\x52\x4b\xfe\x85\xd9\xdc\x1b\xec\x8e\x3b\x37\x01\xdd\x50\xf8\x67\x54\x6f\xbe\x15\xc9\xe4\x05\x7d\xcc\xc2\xdc\xc9\xb8\x32\xba\x86\x7c\x33\xc2\xb5\xdd\x0e\x7c\x8a\x4e\x7a\x18\xef\x39\x34\x9c\x6b\xa4\x9d\x83\xec\x91\xf3\x22\x09\x05\xcb\x01\x52\x1a\x68\x14\xd5\x2b\xa6\xed\x5a\xe1\x48\x3f\x85\xda\x15\xe4\x42\x36\x15\x46\xf4\x50\x03\xc1\xb8\x60\x4d\xca\x20\x2a\xc0\x57\xb5\x9a\x69\xe4\x6a\xd3\xce\x85\xc5\x71\x1a\x95\xca\x2a\xfb\x4c\x3d\x91\x73\xcb\xc9\xa0\xed\x54\xe7\x22\xce\x77\xc3\x6a\x73\x73\x4e\x32\x3f\xbc\xb6\x67\xa8\x54\x55\x4b\x19\xc0\xfe\x52\x34\x1d\x58\x00\x77\xd7\x4b\x91\xdc\xed\x52\xd3\x27\x10\xac\x1e\x6a\x42\x63\xd1\x9f\x39\xd7\x4a\x0f\x2e\x65\x0b\x2e\x2c\xf8\x08\x7d\x73\x2f\x41\x61\x14\x65\x9b\x80\x77\x2c\x42\x2a\xc1\xbe\x9b\x44\xb0\x96\xac\xcb\x69\x77\x6f\xaa\xcd\x65\x92\x80\x95\xe6\x48\x3f\x89\x36\x9c\x49\x55\xab\x45\xc5\xba\x29\xd0\x92\xfb\x93\x30\x17\xd5\xae\x80\x2a\x98\x60\x7b\x11\x29\xaa\x49\x6d\xfc\x13\x13\x6c\x28\x78\x0d\x0d\xfb\x5e\x33\x75\x5a\xc6\x08\x53\xa4\xf1\x5b\xfa\xd5\x37\x19\x2b\x4e\x31\xea\x00\xc5\x31\x86\xfd\x25\x6a\x58\x64\xa8\xb2\xe1\x20\xed\x3f\xe7\xf4\xcf\x51\xb7\x63\x16\x8c\xe6\xfc\x94\x80\x8e\x91\xec\x2f\x8c\x0e\x3c\x04\xf0\x42\xda\xac\xc3\x8a\xb9\x94\xec\x2f\x91\x39\x05\x5d\x46\xbf\x47\xfb\x6a\x80\xaf\x7e\x23\x37\x28\x56\x5c\x34\xc2\x4d\x04\xe5\x45\x4a\xcf\x99\x04\x99\x42\x90\x74\x32\xde\xfd\x24\xa6\x5e\xa9\xa3\x00\x0f\xfb\xcb\x10\xf6\x1e\x50\x20\x94\x6d\x46\x40\x88\x47\x57\x4d\xee\xd9\xe1\x48\x1f\x81\x8c\x13\x66\xf3\x92\x0f\xf4\x00\xa5\x65\x46\x14\x51\x4f\x6f\xf5\x4a\x84\x3d\xc5\x0a\xb0\xc9\x0d\x4e\x10\x86\x1b\x56\x3a\xb0\x61\xca\x67\xa8\x48\xc1\xb1\x4d\xc5\x6f\xa1\x6e\xf7\x73\x54\xf5\xab\xe9\x33\xce\x46\x13\x05\x10\x6e\xce\x17\x6d\xea\x1a\xd9\x51\x7d\x1d\x02\x78\x8c\x1b\xbf\x73\xda\x31\x5d\x77\x8f\x41\xce\xa8\xf9\x54\x91\x17\x51\xbf\xe7\x71\x0e\x0e\x51\xa2\xdd\xcd\xb9\x24\x4a\x32\x20\xa1\x24\xb9\xc7\xd5\xc8\x1c\xf4\x1f\x04\xda\x4f\xa9\x96\x1e\xac\x45\xf3\xc1\xb7\x28\xb5\x17\x94\xba\x5a\x35\x27\xc3\x49\x5f\xa6\x8b\x3b\xd8\x4f\xef\xdd\x63\x2a\xd5\xf0\x0a\xae\xf9\xef\x8c\xd7\x9b\xa8\x9e\xc5\xf0\x56\x7e\xae\xd4\xd3\x99\x3d\x9e\x78\xe8\x35\x22\x2d\xb9\x3b\x3d\xf8\x61\x9a\x62\x24\xfb\x3d\x15\xcb\x50\xb5\xf9\x16\x6a\xa1\x46\xc0\xbf\x55\x33\xac\x5a\xa6\x19\x8c\x95\x66\xc7\x17\x69\x01\xb8\x70\x09\xc6\x52\xfd\x67\x87\xac\x5d\x90\x73\xd2\x5f\xe9\x85\xf3\x99\x10\xdc\xc5\xcc\xe7\x21\xa6\x7a\x37\x51\xf4\x02\xd8\x14\x95\x1e\xac\x46\xc5\xfa\x3c\x12\xfe\x22\x01\xce\x47\xd7\x33\x36\x52\xfd\x6c\x28\xff\xfe\x7c\x86\x88\xed\x6a\x58\x52\xda\x00\x3a\x96\xf9\xb4\xcb\x5d\x8c\xb5\xd7\x31\x06\x3f\x49\x0f\x7f\x9f\xda\x5d\x73\xf5\x41\xd9\xdf\xc7\x53\xa8\x35\x68\xdc\xff\x32\x9a\x4b\xfe\xaa\xec\xd5\x45\xcf\x2e\x49\x8c\x23\xe3\x74\x58\x35\xb7\x1a\x2b\x8c\x32\x25\xac\x6e\x63\xff\x44\x0b\x3c\xac\xb4\x2a\x37\x5e\x0d\x83\x21\x4c\x52\x52\x47\xc6\x69\xaa\x5f\xec\x5e\x37\x39\x52\x17\x9b\xc0\x8b\x98\x85\x15\xac\x84\xd3\xb0\xe0\x39\x9a\x8a\x33\x7d\xf0\x53\xf4\x96\x03\xe0\xaf\xfe\x92\x92\xeb\x41\x95\x7b\x98\xa9\x63\xcf\xc8\x70\x49\x05\x2d\x74\x22\x9d\xf8\x4d\x7e\x11\xbf\x23\xf3\x4c\x32\x23\x9b\xfd\x8a\x32\x16\xfd\xe3\x59\x22\xbe\x81\x6e\x67\xac\xe1\x1a\xab\x50\xf3\xe3\x9d\xc8\x1a\x31\x47\x81\x55\x21\x88\xff\x1c\x19\x63\x08\x6c\x1a\xcd\x00\x34\x8d\x6b\xd0\x63\xfc\x36\xc6\xd8\x3b\xe9\xe0\xfe\x86\xb2\xef\xce\x61\x11\x31\x1c\x35\xdb\x7e\x07\x5f\xcd\xb7\xd4\x0c\xbf\xda\x53\xf6\xe5\x45\xec\xba\xe9\xe0\x02\x54\x6e\x98\xa6\x4c\x4e\x90\xa4\xf6\xbe\x95\x7d\x11\x48\x8c\x73\x4a\x34\xad\x24\x1c\x03\x5f\xb8\x0c\x37\x96\x7c\x0a\xa3\x3c\xff\x12\x96\xa9\x3a\x3f\x86\xd1\x50\x34\xd7\x28\x5c\x66\x35\xbc\x55\x2b\x0d\x3d\x1c\x50\x4f\xe5\xdb\x57\xfb\xbd\x51\xb6\xe6\x1c\xa3\x60\x09\x8c\x83\xab\x86\x18\xe1\x60\x93\xd9\xc7\x39\xea\x5a\x37\xe7\xd7\xec\x78\xd0\xd8\xcb\x32\xb7\xbe\xe0\x45\xb5\x9e\xce\xe7\xd1\x8f\x3c\xf0\xd7\x6f\x36\x0a\x96\x3a\x8c\x03\x2b\x07\x1a\xe1\x50\xb3\x1a\x23\xdf\x8d\x07\xdf\x31\x4a\x56\x9f\xa1\xe6\xb2\x22\x33\x8a\x57\x0d\x37\x6a\xb6\x3f\x68\x78\x6b\xbe\xe3\x41\x49\x85\x11\xf4\x14\x1a\xb5\xbb\x1e\xe6\x5c\x4d\xd1\xb0\x6f\x31\xe9\xf8\x66\xb4\xa1\x87\xbc\xa4\x33\xa4\xd6\xa9\xd8\x30\xdd\x28\x5d\x01\x63\xdf\x52\x3c\x1d\x03\xc9\x0b\xc5\x84\xb2\x15\x48\xf6\x04\x90\xef\x70\x24\x0f\xee\x7c\xfa\x1a\x24\xa5\x0e\x33\xed\x31\x54\x4b\x0e\x5e\xce\xbc\xf8\x1b\x86\xa4\xde\xaa\x7a\x91\x90\x23\x29\x9f\xd4\xbc\xa1\xe6\xfd\x8c\xb1\x13\x28\xcd\xa5\x1c\x1f\x0f\xae\xb2\x68\xdd\xae\xd9\x94\xd0\xc3\xe8\x34\xf4\x51\x64\xb0\x18\x20\x15\x89\xfb\xaa\x6b\x91\x58\xe5\x0f\x33\x98\x4b\xbf\x85\x9c\x53\x5f\x43\x2a\x93\x94\xc4\xe6\xad\xfa\x92\x69\xe3\x8b\xaa\x60\x08\x07\x0d\x26\x22\xf4\x41\xcc\xd7\x0d\xdd\xcf\x9c\xbd\x5e\xf9\x2f\x19\x2f\x7e\xae\xf3\xa8\x57\x28\xe1\x1b\xd9\x27\xf5\x83\x85\xe6\x32\x8e\xb9\xc0\x1a\xda\x35\x6a\x58\x5d\x4d\xe5\xc1\xe1\x17\x32\x56\x01\x2e\x58\x8a\x69\x3c\x87\x7a\xc7\xde\x75\x3a\x43\xd0\x82\x16\xc4\xcb\x79\x94\xb7\xf2\x13\xaa\xe6\x57\xdc\xa4\x92\x8e\xab\x27\x9d\x0e\x6d\x2c\x7d\x34\x1d\xc5\x35\x2a\x6e\x76\xfd\x85\x54\x68\x6a\x29\xa5\x6e\x75\x7b\x1e\xa3\x1a\x3f\x48\x53\xb8\x9b\x76\xfb\x2c\xd7\x93\xad\x4c\x83\x12\xad\x54\x77\xf2\x90\x37\xe2\xfc\x1a\x8b\x5e\x61\x42\x73\xb6\xf2\xf6\xfe\xfa\x75\xaa\x3f\x29\x6d\x14\x55\x7e\x06\x09\x76\xd1\xb9\xe5\x73\xcc\x5c\x34\x97\x4a\xc4\x68\x32\x17\x88\xad\xc3\x7a\x99\x89\x4a\xb7\x71\xeb\x95\x40\x84\x8e\xb0\xef\x20\x4a\xbe\x19\xa9\xfc\x03\xfd\x98\x34\x1f\x4f\x51\x16\xd0\x0a\x6f\xd7\xb8\xaf\xb5\x60\x19\x6e\x10\x7a\x93\xbb\x5d\x65\x2e\x16\xfd\x4f\xa9\x49\x02\x90\xdc\xf5\x32\xf5\x89\x3e\x8e\x7f\xdb\x18\x8e\x8a\xc9\x55\x9f\x4a\x0e\x84\x06\x55\xf9\x10\x6c\x5a\xee\x0d\x2c\x08\x9e\x4a\x00\x6b\x66\x4b\xfe\xda\x55\xf4\x0b\xfd\x91\x94\x72\xa2\x5a\x46\xa3\xe7\xcf\x64\xaa\x59\xb1\x21\x8f\x92\x5c\x0a\x67\x67\x66\x5c\x5a\x0a\x53\xc4\xb9\x0c\x81\x75\x48\xcf\x9d\x45\x7b\x3f\x85\x9f\x57\x99\x8d\x9d\xa9\x6a\xe6\xa8\x99\xaa\x05\xa8\x58\x92\xbe\xda\x92\x32\x95\x74\xa5\xb8\xf0\x53\xb2\x61\x7f\x8d\xd2\x00\xa6\x97\xd0\x6c\x70\x92\xb6\xcb\x2d\x1e\xcc\xd2\xb6\x2d\x47\xb6\xdb\xc0\xc9\x86\x2d\x0b\x49\x92\x31\xc5\xd1\x44\xae\xf4\x08\xd1\xad\x3b\x2c\x0c\x01\xb7\x33\x2d\x9c\xc2\x85\x5d\x4a\xbe\xf5\xfb\x9e\x45\xf5\x8f\xb3\x08\x76\x26\xb2\x86\xcf\x21\xb3\x99\x52\x45\x54\x59\xd4\x57\x67\xe9\x58\xb5\xf9\x2a\x02\xf1\xb0\x5c\xbc\x96\xa5\xe0\x03\x24\x34\x0b\x75\x7b\x1f\x55\x60\xb3\x86\x53\x2d\x7b\xdd\xa8\x36\xf2\x37\x6c\x84\xcd\x9e\xc5\xe9\x26\x45\x32\x3f\xd8\xbc\x2b\xea\x17\x4d\x62\xd8\x65\x73\x66\x71\xad\x19\xb1\x71\xd2\xe1\x29\x5d\xd8\x62\x9c\x2c\xc1\x4f\x20\xec\x80\xae\x69\x3a\x43\x33\xa3\xbe\x8d\xb1\x52\x0a\x82\x28\x81\xe6\x8a\xfc\xaf\x02\xbd\x6c\x6a\x6e\x1c\x7b\xce\x7b\x47\xc6\xa9\xea\x23\xcf\xea\x0b\x08\x76\xcb\xbd\x64\x02\x13\xfb\x11\xaf\x70\x1e\x75\x29\x42\xac\xf4\x0b\xe1\x76\x26\x03\x39\x63\x56\x32\xab\x7a\x92\x00\xdf\x53\x1f\x2b\x25\x13\x6a\x3e\xc8\x75\x46\xc6\xc0\xca\x34\x47\xda\x49\xe6\x8e\xbc\x31\xc1\xee\x65\xf8\x9a\xa3\xc8\x91\xf5\xa4\x89\xdb\x10\x4f\xaf\xb9\x7b\x9b\xd2\xe5\x9e\x01\xc6\x7b\x0f\x4f\x44\x13\xb5\xc0\xce\xd0\x4e\x49\xaf\x5b\x5b\x8a\x06\xe5\xc3\x65\x03\x55\x99\x88\x44\x5a\x00\x33\xed\x41\x54\xb6\xad\x16\x75\x54\x0d\x05\xcf\x33\x61\xbf\x97\x19\xce\xd8\x88\x64\x5b\x82\x8d\xce\x15\xd0\x92\x77\x77\x39\xe9\x7d\x74\x1f\xb7\x95\xe1\x6c\x9c\x02\x2b\xfd\x72\x7a\x59\xb9\x69\x3a\x3c\x95\xcb\x48\x42\x30\x06\x36\x3a\xb7\x6e\xd7\x1f\x69\x9b\xb5\x62\xa2\xb1\x26\xf5\xb6\x1c\x05\xc5\x18\xcb\xbe\x06\x32\x5e\x0f\xd0\xce\x23\xe3\xc4\x67\x84\x42\x08\x31\xe4\x3e\x21\x07\x03\x91\xa0\x25\x82\xa4\x0b\x4c\x68\x12\xcf\x24\x57\x2d\xfb\xfe\x14\x04\xea\xbf\x8b\xc7\xb7\x56\x63\x7c\xf4\xe0\x35\x5b\xef\xa7\xc3\x70\xf1\xc4\xe2\xbf\xe8\x64\x92\xe3\x04\x24\x8c\x8d\x5e\x0a\x68\xf9\x68\xae\xbe\x64\xd0\x79\x8a\x56\x17\x4b\xcb\xb4\xbe\x77\x11\xfc\x0e\x54\xe4\x4f\x46\xd9\xda\xb1\x4c\x4b\xf7\x93\x28\x33\xe6\x36\x95\x2c\x44\xd3\xc1\x77\x95\x85\xc4\xd6\xa1\x6d\xba\x72\x26\x31\xc5\x1c\xc1\x35\xc2\xa4\x5f\xe3\x29\xc8\x2a\xa6\x98\xf3\x5b\x8c\x73\x39\xd4\xe9\xe7\x82\x7e\x93\xf1\x8d\xcc\x35\x7d\x98\x5c\x45\x72\x58\xb9\xe4\x0d\x82\x9e\x5d\xe4\xd6\x13\xdc\xb8\x4c\xf4\xdd\x7c\xac\xd8\xd6\x52\xb5\xa5\x3a\xd1\xf9\x0a\x21\xad\xff\x0c\xe5\xb9\x05\xcc\x4f\x35\x53\x45\x77\xb3\x94\x7b\x8e\x40\x7f\x8d\xac\xa1\x73\xd5\x14\x09\x29\x81\x86\xf5\xb4\xd5\xed\x6a\x4f\x11\x92\x14\x03\xb5\x5b\xef\xe4\xce\x2d\xd7\xb5\xf0\xb4\x3e\xb5\xb7\x99\x46\x0a\x42\x49\x69\x6b\xb6\xde\x41\x18\x4c\x52\x22\x62\x94\xd3\x4e\x9e\x7b\x15\x6b\x06\x1e\x8c\xd2\x64\x76\x71\xe5\x30\x8f\x52\xc8\x7b\x25\xc9\x60\xf3\x36\x72\xf9\x64\x72\x75\x9e\x9a\x5c\xb7\xfb\x01\xf8\x6a\xbf\x8e\x70\x3c\xb2\x1a\x49\xd0\x99\xec\x07\x1a\xb6\xd0\x5c\x2d\xaa\x90\x88\x2e\x7a\xb8\x6f\x01\xeb\xab\xfd\x96\xeb\x8f\x27\x9d\xc9\x0c\x5d\x4f\x28\x28\x8a\x51\xb4\x41\x49\x0d\x53\x7a\xcc\xa4\x89\x75\xa6\x73\x6b\x60\x4a\x3a\x93\x91\xa0\x2c\xa6\xa2\xb2\xb6\xd8\xae\xb3\xd3\x19\xca\x6b\x33\x93\x51\x62\xa8\xd9\x76\x37\xbd\xf3\xe6\x18\x58\xbe\xdb\x92\x16\xe6\xd0\xdb\x7a\xe5\xe1\xa0\xba\xe3\x3f\x45\xbd\xf4\x49\x75\x63\xd0\x7b\x0a\x1f\xed\xee\x13\x18\x2a\x5e\x54\x31\x50\x06\xfa\x6a\xbe\x62\x8e\x3c\x9e\x39\xeb\x24\xe6\xd7\xeb\xe2\xea\x2d\x3b\x33\xed\xd3\xdc\xdd\xa9\x5a\xa3\xd4\x5c\x19\xdf\x6e\x23\x58\x39\x38\xa8\x58\x7f\x81\x02\x94\x3d\x7a\x31\x3d\x31\x6b\xe6\x98\x56\x98\xea\x2e\x34\xc8\xe9\x45\xd5\xe6\x9b\xa8\xa6\x34\x27\x71\x2d\xad\x5a\x6a\x1f\xa6\x91\x54\x63\xa9\x7d\x25\x14\x36\xee\x7f\xb3\xc5\x38\x07\x2d\x94\x67\x5b\x8f\xe6\x4e\x02\xdf\xe7\xc4\x9b\xd5\x1a\x20\x83\x58\x6a\x86\xfd\xa5\x0a\xb4\x62\x8c\xc5\x81\x94\xee\x33\x55\x8d\x6b\x4f\x1d\x49\xcf\xba\x8a\xa1\xe6\x2e\x16\x05\x5f\xa3\x6c\xcd\xe9\x74\x2c\x8b\x09\x5a\xec\x4b\x28\xb1\xaa\xc3\x35\x89\x9d\x71\xc2\xe3\x1b\xb4\xb8\x22\x30\x53\xaa\x8f\x73\xcd\xbf\xd1\x6b\x9f\xc0\x29\x2d\x55\x95\x8b\x32\x60\xfb\xe9\xf1\x6f\x46\x73\xf1\xc2\x16\x20\x64\x2d\xe1\x71\x52\xda\x20\x86\xc3\x49\x6a\x69\xb1\xd9\xda\x9d\x72\xac\x1b\xdf\xc9\xed\xe4\x11\xb6\x1f\x7f\xa1\xdd\x3e\x12\x7f\x6a\x5e\x69\x21\x96\x9d\xd4\x71\x3f\x13\x54\xda\xcb\x4e\x15\x3a\xb8\xac\x49\x08\x89\x11\xa7\xe0\xcc\x3c\x4b\x7d\x52\x7b\xdd\xc1\x63\x96\x4f\x95\x06\x28\x32\xc5\x58\x08\xda\x4a\xf5\xeb\x68\xb3\x6a\xe9\x48\x63\x92\xaf\xa4\xd8\x0a\xac\x30\x51\x4c\xab\x7a\xd3\x8d\xac\x9e\x78\xf6\xdc\x86\x64\x65\xa2\xab\xf3\xf9\x4c\x4e\x92\x19\xd6\x16\x31\x3a\xdc\x42\x72\x99\x4e\x46\x00\x0b\x58\x1e\xed\xbc\xeb\xf1\xe0\x76\x2e\xd7\x8a\x9b\x14\xcf\xe0\x4b\x50\x43\xba\xd7\xab\xd7\x19\x35\xab\x23\x74\x73\x1c\x47\x4b\x71\x2f\x65\xa0\x34\x91\x84\xcd\x95\xcb\x58\x79\x0b\x25\x3f\x58\x1e\x44\xc6\xf2\x2b\xea\x25\xe2\x4f\x5a\x5d\x99\xde\xd6\xd4\x8a\x48\x57\xe2\x7c\x79\xc4\xfd\xf4\x60\x1d\x2a\xf3\x2f\x6b\x1f\x6c\x64\xaa\xe4\x0c\x92\x67\x4b\x1e\x6e\xe8\x3c\x02\x4e\x00\xeb\x0d\xe0\x7d\xcd\x83\xeb\x87\x4e\x45\x20\x32\xbc\xc5\x97\x55\xb8\x40\xcb\x7e\x86\x9f\xb0\xa7\xf4\x6d\x3a\x88\x03\xdc\x9b\x3a\xce\x3f\x67\xd6\xb9\x6a\x61\x6f\xf5\x0a\x3e\x93\x27\x22\x7b\xf9\x47\xbd\x8a\x35\x2e\x40\xe7\xd2\x5e\x93\x79\x32\x4b\x0e\xe7\x1b\x8b\xe7\xd1\x11\xbe\xad\xce\xc3\xd4\x73\xd5\x67\xce\x14\x61\xd4\x6c\xbf\x97\xaf\x51\xbf\x6a\x5b\xb2\x91\x0d\x44\xea\xf5\xbb\x1f\x61\xf8\x9a\x62\xee\x1b\x01\x2b\xe1\x87\x6a\xfc\x4e\x49\x13\xae\xed\x35\x15\xde\x76\xe9\x89\x76\xb0\xd4\xbb\x9f\x52\x7e\x52\x4b\x1b\x83\xcc\x61\xaf\x50\x95\x47\x22\x24\x71\x78\xcd\x68\x55\x34\xb8\x99\x42\xba\x73\xa6\xd1\x33\x9e\xcd\x98\x1b\x55\x61\x9d\x49\xfa\x68\x32\xc3\xa6\x92\x77\x93\xd5\x71\xc9\x0b\xa8\x90\xb7\x10\xd5\xdb\x6e\xe5\x21\xde\x72\x7a\x5d\xee\x46\x02\xad\x76\xfa\x08\x7a\xe2\xcc\x41\x4f\x2b\x2d\x12\xdc\xbe\xda\x35\xac\x83\xc7\x71\x00\x03\xec\x4f\xb5\xe8\x16\x89\x60\x03\x78\x8f\xe6\x79\xed\xc0\x0b\xe1\x3f\xdc\xf4\x68\x8c\x01\xcf\x6f\xff\x14\x08\xe1\x26\x9e\x2b\xd7\xd6\xad\x3f\x8b\xa5\xdd\xdd\xca\x6b\x77\x19\xc5\x97\x64\xc9\x83\xa8\x66\x1f\x33\xc1\x9f\x46\x35\xca\xa3\xc0\xc4\x66\xcc\xdd\x04\x94\x1c\xa0\xcb\x19\x57\xcb\x66\xda\x63\xe5\xc6\x4b\x99\xdb\x2e\x67\xce\x9b\xc3\xc2\xe0\x54\xfa\x82\x91\xb4\x00\x1b\xea\xf7\xbd\xcc\x77\x49\xf1\xf8\xea\x29\xfb\x80\x6b\x74\x00\xac\x6c\x22\x5b\x47\xc0\x3a\x98\x36\x52\xb2\xdf\xf3\xed\xe2\x4d\x3f\x05\x56\xa6\xc6\x13\x0f\xde\xf4\xcb\xc3\xab\x2c\xd8\xd7\x1a\x68\x78\xdc\x56\xf4\xfc\x24\x7f\xe9\xeb\x48\xea\x72\x25\x32\xfa\x3f\x49\x85\xe3\xcf\x16\x98\x04\xc8\x01\xb9\xd2\xb9\x98\x0d\x9a\xb6\xa7\x87\x1b\x61\x93\x23\x99\x08\xf7\x85\x1f\x8d\x45\xff\x49\xc9\x6d\xa4\x24\xff\xc8\x24\xe1\x36\x4a\x36\x8b\xbb\xe8\x94\x7a\x01\x4f\x18\x7f\xcf\x50\xf2\x3a\xcf\xb8\xae\x23\x13\x58\xbb\x32\xb5\x8c\x82\x10\xc2\x3a\xd2\x24\xd6\xb2\xec\xf3\xf0\x8d\xc8\x2d\x1d\x7d\x69\x1e\x93\x70\x74\x83\xfe\x17\x63\x4b\x9f\x49\xc8\x63\xbd\x7d\x5d\x28\xd0\x90\x2f\xb5\x6a\xe3\x8f\x57\xc0\x5b\xf2\x21\xab\xa9\x33\x91\xd2\x6d\x86\xb2\x71\x25\x61\xe5\xac\xac\x54\xd5\x66\xe5\x70\x22\x4c\xe7\x52\x92\x94\xf8\x59\xbf\x2e\x60\x72\x7f\x31\x0f\x00\x1e\xa1\x27\x97\x23\x20\x19\x61\x53\xe1\x28\x6b\xc8\x5f\x58\xac\x64\xf1\xed\xff\x87\x6a\x6b\x49\x32\x8e\xb4\x49\xac\xa5\x52\xbc\xd9\x37\x0f\x1b\x3a\x3a\xf7\x10\xc0\x91\x89\x06\x03\xf6\x1b\x7b\x5d\x18\xab\x69\x98\xe0\xf7\xfb\xe7\x37\x1d\x5c\xd8\xd8\xb0\x61\x02\xed\xec\x54\x75\xba\xaf\xf3\xd4\x23\xcc\x03\x71\x95\xb0\xd0\x89\x45\xbd\xb9\x9a\x4f\x5c\xe2\x71\xa5\xcf\x9d\x73\xb9\xb9\xa4\x4a\x5d\x45\xfc\x0c\x79\x3c\xf4\xb6\x39\x3a\xb3\x78\xf8\x85\x0a\x85\x32\x40\xe5\xe0\xe6\xc8\x0e\xfd\x57\x76\x1f\x80\x9f\xaf\x80\x5e\xea\xd0\x84\xc8\xa0\x16\x2a\xdd\x7a\xe2\xc4\x89\xea\x7d\xeb\x2a\x3e\x5f\xb5\xef\x13\x3c\x46\x1b\xff\x8d\x51\xb7\x6e\x06\x9a\xd6\x75\x6a\xdc\xf7\x28\x49\x77\xaa\x13\x0b\x51\x63\x39\x7d\x4c\x6c\xe2\xc8\xe4\x90\x4f\xde\x1e\xb4\xd7\x24\x47\x37\x74\xd3\xf6\xad\x5a\x6a\xcc\x1c\xda\x1b\x9f\xf8\x9c\xef\xa6\xd1\xec\xc3\xf2\xfe\xed\xbc\x25\x4c\x1c\x9b\x78\xdd\x9e\x84\x13\xc7\xa8\xeb\x7e\x17\x60\x67\xef\x0b\x41\x4f\x86\x31\xc1\x30\x9e\xa7\xf4\x1a\xb4\x70\xa1\x19\x82\x09\x38\xd0\xb4\x35\x3e\x87\xf7\x56\x2d\x43\x1d\x07\x45\x8f\x6c\xe2\x9d\x72\xc5\x20\x44\xad\x08\x36\x6d\xa1\x7a\x0f\x35\xbb\x5a\x55\x6b\x2d\xc7\xb7\xbc\x13\xe9\x92\xf9\x41\x66\x95\x4f\xb6\xec\xf9\xe9\xbb\x0e\x03\x8e\x2e\xc5\x9f\x18\xec\x53\xc0\x81\xb1\x81\x30\xde\xa2\xe3\x08\x39\x99\xdd\x34\x16\xbe\xa4\x1c\x8f\x19\x5f\x09\x89\x79\x6e\x32\xd3\xd3\xc6\x22\x79\xbe\x51\x79\x66\xf1\xce\xe6\xc7\xca\x32\xee\x15\x75\x1e\xe6\xce\xb9\x34\xba\x74\x87\xbf\x25\xe6\xd2\x76\xe7\xd1\xec\xd6\x76\x78\x52\x64\xa0\x78\x91\x9f\xd5\x18\xbf\x27\xf0\xa0\x6c\x36\x7f\x75\x37\xde\xa2\x65\xc1\xd5\xfb\x1e\x96\x7c\xb7\xd2\x23\xa7\xab\x57\xa2\xe5\xeb\xce\xe5\x5b\x87\xed\x48\xe3\x9b\x7d\x47\x1a\x8f\x90\xe8\xcc\xbc\x2c\xf2\x1b\xf7\xf3\x94\xb2\xcf\xcd\x2c\x0d\x5f\x56\xfb\x57\x6c\x98\xca\xdc\xf9\xfd\xc3\x26\x1d\x32\x50\x32\x42\x82\x2d\x08\x87\x30\xf6\x68\x7e\xd8\xf2\xb3\x01\x0b\x11\x2b\x57\x42\xeb\xef\x03\x8f\xff\xf1\x00\x5f\x98\x0f\x34\x9c\x43\x90\xda\xff\x01\x16\x20\x57\xa9\xdc\xb8\x6a\xd3\x34\xbe\x21\xf8\x42\x86\xaa\x26\xce\x3d\xb5\xb7\x1c\xf2\xbd\x40\x89\xf3\x0d\x21\xa9\x28\xcf\xbf\x18\x9e\x92\x45\x87\x05\x2c\x61\x88\x4a\x12\xe4\x09\x46\x1e\x5f\x83\xae\x88\xae\x77\x24\xdf\xc7\x04\x70\x74\xc3\xed\x1f\x22\x2b\xd9\x89\x3b\x09\xfc\x36\x4a\x22\x13\x69\x13\x90\x3e\xe0\x21\xa6\xa8\xe3\x59\x62\xae\x62\x7a\xf9\x77\xe5\xc8\xe4\x68\xc7\x49\x0f\x2d\x3e\x5b\x72\x72\x31\x83\xf2\xfc\x8b\x08\x98\x55\x58\x5b\x05\x03\x87\x09\x53\x24\xc9\xe0\x9b\xfd\xdf\x13\xec\x11\xdb\x6e\x94\xc6\x63\x0a\x38\xba\x28\x3d\xfa\x20\x56\x60\x0f\xd0\x2f\x4d\xb7\xdb\x2d\x9a\x2d\x33\x8f\x27\x22\xf7\x31\x2d\x95\xd4\xd1\x6c\x66\xde\xa2\x20\x77\x08\xb0\x4b\x4a\x3e\x2f\xe6\x30\x23\xbc\x95\x2b\x98\x13\xa3\x8b\x1d\xc1\xf7\x11\x3b\xad\x8e\xac\x1d\xf1\xe8\xd7\x86\x2d\x38\x27\x18\x36\xbe\x08\x56\x2d\x46\xf5\xba\x33\xf9\x36\x60\x02\x0b\x88\xf9\xcc\xcd\xab\x94\xc4\x4c\x07\x17\xe5\x79\xf4\xbb\xe5\x0e\xf2\x34\x52\xdf\xce\xaf\x2e\xa1\xf6\xfc\x0c\xb0\xb2\x72\xdb\xbb\x48\xcf\x31\x6a\xc6\x6c\x58\x0f\x9c\x86\xa9\x94\xc9\x7d\xe4\xee\x68\x86\x12\xe8\xf6\x9e\x70\xb0\xa6\x75\x67\xe7\x31\x7b\x3b\x89\x3b\xe9\x3c\x51\xb9\x80\x55\x14\xcf\xb2\x12\x28\x92\x5f\xe1\xc9\x4f\x10\xa5\x98\x97\xfa\xb6\xbd\x92\xef\x48\x48\x4d\x58\xfe\x48\xa6\x1d\xf9\xd8\x95\xf3\xe0\xcc\xed\xcc\x1f\x8d\xdb\x70\x0d\xd5\x79\x3c\x7f\x88\xda\x89\xe7\x70\xcc\xe6\xf9\x6b\x3b\xfe\xb2\x2c\x1c\x88\xbf\xd5\x14\xa0\xcc\xf0\x10\x08\xa2\x9e\x15\xd6\x63\xbd\xf3\xf0\x14\x09\x3d\x6a\x35\x4e\xa4\xf6\x7f\x0c\x70\xe2\xa6\xc5\xcb\xd1\x93\x75\xd1\xe9\xdc\xfc\x34\x9e\xc1\x8d\x22\x03\x7a\x52\xb2\x69\xd4\x37\x2b\x61\xe9\xd4\x82\x83\x44\xb7\x92\x19\xe8\xbc\xd6\xbf\xc2\x49\x5c\xe7\x68\xae\xff\x57\x00\xb7\x26\x94\xbf\x16\x72\x68\x2e\xb8\x34\x3f\xac\x21\x2f\xf4\xbe\x17\xa3\x9e\x0c\x38\x26\x12\x6d\xbd\xd7\xf1\xfb\xe3\x1c\xf8\x7f\xc6\x81\x7f\x00\x56\x56\x40\x50\x4a\x47\x41\x4c\x00\x00\x00\x00\x49\x45\x4e\x44\xae\x42\x60\x82\x01\x00\x00\xff\xff\x17\xf7\x20\xf4\x47\x15\x00\x00")
 
 func cydiaiconPngBytes() ([]byte, error) {
@@ -231,11 +183,9 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*BinAsset, error){
-	".dpkg-gettext.pl":   DpkgGettextPl,
-	".dpkg-scanpackages": DpkgScanpackages,
-	"CydiaIcon.png":      cydiaiconPng,
-	"CydiaIcon@2x.png":   cydiaicon2xPng,
-	"CydiaIcon@3x.png":   cydiaicon3xPng,
+	"CydiaIcon.png":    cydiaiconPng,
+	"CydiaIcon@2x.png": cydiaicon2xPng,
+	"CydiaIcon@3x.png": cydiaicon3xPng,
 }
 
 // AssetDir returns the file names below a certain
@@ -279,11 +229,9 @@ type bintree struct {
 }
 
 var _bintree = &bintree{nil, map[string]*bintree{
-	".dpkg-gettext.pl":   &bintree{DpkgGettextPl, map[string]*bintree{}},
-	".dpkg-scanpackages": &bintree{DpkgScanpackages, map[string]*bintree{}},
-	"CydiaIcon.png":      &bintree{cydiaiconPng, map[string]*bintree{}},
-	"CydiaIcon@2x.png":   &bintree{cydiaicon2xPng, map[string]*bintree{}},
-	"CydiaIcon@3x.png":   &bintree{cydiaicon3xPng, map[string]*bintree{}},
+	"CydiaIcon.png":    &bintree{cydiaiconPng, map[string]*bintree{}},
+	"CydiaIcon@2x.png": &bintree{cydiaicon2xPng, map[string]*bintree{}},
+	"CydiaIcon@3x.png": &bintree{cydiaicon3xPng, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory
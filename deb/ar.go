@@ -0,0 +1,107 @@
+package deb
+
+import (
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+	"github.com/ulikunitz/xz/lzma"
+)
+
+const (
+	arMagic      = "!<arch>\n"
+	arHeaderSize = 60
+)
+
+var errNotDeb = errors.New("not a debian archive (missing ar header)")
+
+// arMember represents a single file inside an ar container.
+type arMember struct {
+	name   string
+	offset int64
+	size   int64
+}
+
+// readAr returns the members of the ar container r.
+func readAr(r io.ReaderAt) ([]arMember, error) {
+	magic := make([]byte, len(arMagic))
+	if _, err := r.ReadAt(magic, 0); err != nil || string(magic) != arMagic {
+		return nil, errNotDeb
+	}
+
+	var members []arMember
+	offset := int64(len(arMagic))
+	header := make([]byte, arHeaderSize)
+	for {
+		n, err := r.ReadAt(header, offset)
+		if n == 0 && err == io.EOF {
+			return members, nil
+		}
+		if n < arHeaderSize {
+			return nil, errors.New("truncated ar header at offset " + strconv.FormatInt(offset, 10))
+		}
+		if string(header[58:60]) != "`\n" {
+			return nil, errors.New("malformed ar header at offset " + strconv.FormatInt(offset, 10))
+		}
+		size, err := strconv.ParseInt(strings.TrimSpace(string(header[48:58])), 10, 64)
+		if err != nil || size < 0 {
+			return nil, errors.New("malformed ar member size at offset " + strconv.FormatInt(offset, 10))
+		}
+		// GNU ar terminates names with a slash.
+		name := strings.TrimSuffix(strings.TrimSpace(string(header[0:16])), "/")
+		offset += arHeaderSize
+		members = append(members, arMember{name: name, offset: offset, size: size})
+		// Members are aligned on even byte boundaries.
+		offset += size + size%2
+	}
+}
+
+// findMember returns the first member whose name starts with prefix.
+func findMember(members []arMember, prefix string) (arMember, bool) {
+	for _, m := range members {
+		if strings.HasPrefix(m.name, prefix) {
+			return m, true
+		}
+	}
+	return arMember{}, false
+}
+
+// decompress wraps r with a decompressor chosen by the extension of a
+// control.tar or data.tar member name. (control.tar.gz)
+func decompress(name string, r io.Reader) (io.ReadCloser, error) {
+	switch path.Ext(name) {
+	case ".tar":
+		return ioutil.NopCloser(r), nil
+	case ".gz":
+		return gzip.NewReader(r)
+	case ".bz2":
+		return ioutil.NopCloser(bzip2.NewReader(r)), nil
+	case ".xz":
+		xr, err := xz.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.NopCloser(xr), nil
+	case ".lzma":
+		lr, err := lzma.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.NopCloser(lr), nil
+	case ".zst":
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return nil, errors.New("unsupported compression for member " + name)
+	}
+}
@@ -0,0 +1,186 @@
+package deb
+
+import (
+	"archive/tar"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+// MaintainerScripts are the control.tar members that dpkg (and Cydia) run during installation.
+var MaintainerScripts = []string{"preinst", "postinst", "prerm", "postrm", "config", "extrainst_"}
+
+// Archive represents the contents of a debian package (.deb) file.
+type Archive struct {
+	version     string
+	control     *Control
+	controlFile []byte
+	files       []File
+	scripts     map[string][]byte
+}
+
+// File represents a single entry in the data.tar member of a debian package.
+type File struct {
+	Name     string
+	Size     int64
+	Mode     os.FileMode
+	Linkname string
+}
+
+// Open opens the debian package at path and reads its contents.
+func Open(path string) (*Archive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	a, err := Read(f)
+	if err != nil {
+		return nil, errors.New(path + ": " + err.Error())
+	}
+	return a, nil
+}
+
+// Read reads a debian package from r. (ar container with debian-binary, control.tar and data.tar)
+func Read(r io.ReaderAt) (*Archive, error) {
+	members, err := readAr(r)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Archive{}
+
+	binary, ok := findMember(members, "debian-binary")
+	if !ok {
+		return nil, errors.New("debian archive has no debian-binary member")
+	}
+	version, err := ioutil.ReadAll(io.NewSectionReader(r, binary.offset, binary.size))
+	if err != nil {
+		return nil, err
+	}
+	a.version = strings.TrimSpace(string(version))
+
+	a.controlFile, a.scripts, err = readControlTar(r, members)
+	if err != nil {
+		return nil, err
+	}
+	c, err := NewControl().ParseString(string(a.controlFile))
+	if err != nil {
+		return nil, err
+	}
+	a.control = c
+
+	data, ok := findMember(members, "data.tar")
+	if !ok {
+		return nil, errors.New("debian archive has no data.tar member")
+	}
+	err = walkTar(r, data, func(hdr *tar.Header, tr io.Reader) error {
+		a.files = append(a.files, File{
+			Name:     hdr.Name,
+			Size:     hdr.Size,
+			Mode:     hdr.FileInfo().Mode(),
+			Linkname: hdr.Linkname,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ReadControlFile returns the control file of the debian package r without reading its data.tar.
+func ReadControlFile(r io.ReaderAt) ([]byte, error) {
+	members, err := readAr(r)
+	if err != nil {
+		return nil, err
+	}
+	controlFile, _, err := readControlTar(r, members)
+	return controlFile, err
+}
+
+// readControlTar returns the control file and maintainer scripts stored in the control.tar member.
+func readControlTar(r io.ReaderAt, members []arMember) ([]byte, map[string][]byte, error) {
+	control, ok := findMember(members, "control.tar")
+	if !ok {
+		return nil, nil, errors.New("debian archive has no control.tar member")
+	}
+
+	var controlFile []byte
+	scripts := make(map[string][]byte)
+	err := walkTar(r, control, func(hdr *tar.Header, tr io.Reader) error {
+		name := path.Clean(hdr.Name)
+		if name == "control" {
+			b, err := ioutil.ReadAll(tr)
+			controlFile = b
+			return err
+		}
+		for _, s := range MaintainerScripts {
+			if name == s {
+				b, err := ioutil.ReadAll(tr)
+				scripts[s] = b
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if controlFile == nil {
+		return nil, nil, errors.New("control.tar has no control file")
+	}
+	return controlFile, scripts, nil
+}
+
+// walkTar calls fn for every entry of the compressed tar member m.
+func walkTar(r io.ReaderAt, m arMember, fn func(*tar.Header, io.Reader) error) error {
+	zr, err := decompress(m.name, io.NewSectionReader(r, m.offset, m.size))
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.New(m.name + ": " + err.Error())
+		}
+		if err := fn(hdr, tr); err != nil {
+			return errors.New(m.name + ": " + err.Error())
+		}
+	}
+}
+
+// FormatVersion returns the debian-binary format version of the package. (2.0)
+func (a *Archive) FormatVersion() string {
+	return a.version
+}
+
+// Control returns the parsed control file of the package.
+func (a *Archive) Control() *Control {
+	return a.control
+}
+
+// ControlFile returns the control file of the package as it is stored in control.tar.
+func (a *Archive) ControlFile() []byte {
+	return a.controlFile
+}
+
+// Files returns the entries of the package's data.tar in archive order.
+func (a *Archive) Files() []File {
+	return a.files
+}
+
+// Scripts returns the maintainer scripts of the package keyed by name. (postinst)
+func (a *Archive) Scripts() map[string][]byte {
+	return a.scripts
+}
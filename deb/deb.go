@@ -2,13 +2,11 @@
 package deb
 
 import (
-	"errors"
-	"regexp"
 	"strconv"
-	"strings"
 )
 
 // DpkgInterface is an interface for control files and Package files in a existing repo.
+// ParseString is not part of it, as it returns the concrete type.
 type DpkgInterface interface {
 	Package() string
 	Name() string
@@ -23,15 +21,26 @@ type DpkgInterface interface {
 	Depiction() string
 	Homepage() string
 	Sponsor() string
+	Field(name string) string
+	Paragraph() *Paragraph
+}
+
+// PackagesInterface is an interface for Package files, which also locate the deb of a package and hash it.
+type PackagesInterface interface {
+	DpkgInterface
 	Filename() string
 	Size() int
 	MD5Sum() string
 	SHA1() string
 	SHA256() string
-
-	ParseString()
 }
 
+// Control and Packages implement the interfaces.
+var (
+	_ DpkgInterface     = (*Control)(nil)
+	_ PackagesInterface = (*Packages)(nil)
+)
+
 // Control represents a structure of a debian/cydia tweak control file.
 type Control struct {
 	packageID     string
@@ -47,6 +56,7 @@ type Control struct {
 	sponsor       string
 	section       string
 	installedsize int
+	paragraph     *Paragraph
 }
 
 // Packages represents a structure of a tweak Packages file.
@@ -69,6 +79,7 @@ type Packages struct {
 	author        string
 	sponsor       string
 	name          string
+	paragraph     *Paragraph
 }
 
 var (
@@ -272,106 +283,129 @@ func (p *Packages) SHA256() string {
 	return p.sha256
 }
 
+// Field returns the value of any control field, including fields without an accessor. (Icon, Conflicts...)
+func (c *Control) Field(name string) string {
+	if c.paragraph == nil {
+		return ""
+	}
+	return c.paragraph.Get(name)
+}
+
+// Field returns the value of any Packages field, including fields without an accessor. (Icon, Conflicts...)
+func (p *Packages) Field(name string) string {
+	if p.paragraph == nil {
+		return ""
+	}
+	return p.paragraph.Get(name)
+}
+
+// Paragraph returns every field of the control file in order.
+func (c *Control) Paragraph() *Paragraph {
+	return c.paragraph
+}
+
+// Paragraph returns every field of the Packages entry in order.
+func (p *Packages) Paragraph() *Paragraph {
+	return p.paragraph
+}
+
 // ParseString parses a string f returns a *Control struct.
 func (c *Control) ParseString(f string) (*Control, error) {
-	parseMap := parse(f)
-	if len(parseMap) == 0 {
-		return nil, errors.New("unable to parse control file")
+	para, err := ParseParagraph(f)
+	if err != nil {
+		return nil, err
 	}
+	return controlFromParagraph(para)
+}
 
-	if _, exists := parseMap["Homepage"]; exists != true {
-		parseMap["Homepage"] = DefaultHomePage
-	}
-	if _, exists := parseMap["Sponsor"]; exists != true {
-		parseMap["Sponsor"] = DefaultSponsor
+// ParseString parses a string f returns a *Packages struct.
+func (p *Packages) ParseString(f string) (*Packages, error) {
+	para, err := ParseParagraph(f)
+	if err != nil {
+		return nil, err
 	}
+	return packagesFromParagraph(para)
+}
 
-	// Necessary string conversion.
-	is, sterr := strconv.Atoi(parseMap["Installed-Size"])
+// controlFromParagraph creates a *Control struct from the fields of para.
+func controlFromParagraph(para *Paragraph) (*Control, error) {
+	// Necessary string conversion. (Installed-Size is optional)
+	is, sterr := atoiOptional(para.Get("Installed-Size"))
 	if sterr != nil {
 		return nil, sterr
 	}
 
 	cntrl := &Control{
-		packageID:     parseMap["Package"],
-		name:          parseMap["Name"],
-		depends:       parseMap["Depends"],
-		version:       parseMap["Version"],
-		arch:          parseMap["Architecture"],
-		description:   parseMap["Description"],
-		homepage:      parseMap["Homepage"],
-		depiction:     parseMap["Depiction"],
-		maintainer:    parseMap["Maintainer"],
-		author:        parseMap["Author"],
-		sponsor:       parseMap["Sponsor"],
-		section:       parseMap["Section"],
+		packageID:     para.Get("Package"),
+		name:          para.Get("Name"),
+		depends:       para.Get("Depends"),
+		version:       para.Get("Version"),
+		arch:          para.Get("Architecture"),
+		description:   para.Get("Description"),
+		homepage:      getDefault(para, "Homepage", DefaultHomePage),
+		depiction:     para.Get("Depiction"),
+		maintainer:    para.Get("Maintainer"),
+		author:        para.Get("Author"),
+		sponsor:       getDefault(para, "Sponsor", DefaultSponsor),
+		section:       para.Get("Section"),
 		installedsize: is,
+		paragraph:     para,
 	}
 
 	return cntrl, nil
 }
 
-// ParseString parses a string f returns a *Packages struct.
-func (p *Packages) ParseString(f string) (*Packages, error) {
-	parseMap := parse(f)
-	if len(parseMap) == 0 {
-		return nil, errors.New("unable to parse Packages file")
-	}
-
-	if _, exists := parseMap["Homepage"]; exists != true {
-		parseMap["Homepage"] = DefaultHomePage
-	}
-	if _, exists := parseMap["Sponsor"]; exists != true {
-		parseMap["Sponsor"] = DefaultSponsor
-	}
-
-	// Necessary string conversion for Installed-Size.
-	is, sterr := strconv.Atoi(parseMap["Installed-Size"])
+// packagesFromParagraph creates a *Packages struct from the fields of para.
+func packagesFromParagraph(para *Paragraph) (*Packages, error) {
+	// Necessary string conversion for Installed-Size. (Installed-Size is optional)
+	is, sterr := atoiOptional(para.Get("Installed-Size"))
 	if sterr != nil {
 		return nil, sterr
 	}
 
-	// Necessary string conversion for Installed-Size.
-	size, serr := strconv.Atoi(parseMap["Size"])
+	// Necessary string conversion for Size.
+	size, serr := atoiOptional(para.Get("Size"))
 	if serr != nil {
 		return nil, serr
 	}
 
 	pkgs := &Packages{
-		packageID:     parseMap["Package"],
-		name:          parseMap["Name"],
-		depends:       parseMap["Depends"],
-		version:       parseMap["Version"],
-		arch:          parseMap["Architecture"],
-		description:   parseMap["Description"],
-		homepage:      parseMap["Homepage"],
-		depiction:     parseMap["Depiction"],
-		maintainer:    parseMap["Maintainer"],
-		author:        parseMap["Author"],
-		sponsor:       parseMap["Sponsor"],
-		section:       parseMap["Section"],
+		packageID:     para.Get("Package"),
+		name:          para.Get("Name"),
+		depends:       para.Get("Depends"),
+		version:       para.Get("Version"),
+		arch:          para.Get("Architecture"),
+		description:   para.Get("Description"),
+		homepage:      getDefault(para, "Homepage", DefaultHomePage),
+		depiction:     para.Get("Depiction"),
+		maintainer:    para.Get("Maintainer"),
+		author:        para.Get("Author"),
+		sponsor:       getDefault(para, "Sponsor", DefaultSponsor),
+		section:       para.Get("Section"),
 		installedsize: is,
 		size:          size,
-		md5sum:        parseMap["MD5sum"],
-		sha1:          parseMap["SHA1"],
-		sha256:        parseMap["SHA256"],
-		filename:      parseMap["Filename"],
+		md5sum:        para.Get("MD5sum"),
+		sha1:          para.Get("SHA1"),
+		sha256:        para.Get("SHA256"),
+		filename:      para.Get("Filename"),
+		paragraph:     para,
 	}
 
 	return pkgs, nil
 }
 
-// parse transforms a given control or packages file f and returns a key value map.
-func parse(f string) map[string]string {
-	var matches = make(map[string]string)
-
-	keys := regexp.MustCompile(`(?m:^\w\S+)`)    // eg. should match: Packages:
-	values := regexp.MustCompile(`(?m:( )\S.+)`) // eg. should match: com.yourcompany.tweakexample
-
-	for c, v := range values.FindAllString(f, -1) {
-		field := strings.Replace(keys.FindAllString(f, -1)[c], ":", "", -1)
-		matches[field] = strings.Replace(v, " ", "", 1) // replace at the start.
+// getDefault returns the value of the field name or def when the field does not exist.
+func getDefault(para *Paragraph, name string, def string) string {
+	if v, exists := para.Lookup(name); exists {
+		return v
 	}
+	return def
+}
 
-	return matches
+// atoiOptional is like strconv.Atoi but returns 0 for a missing field.
+func atoiOptional(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(s)
 }
@@ -1 +1,325 @@
 package deb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"testing"
+)
+
+// Testing reading debs with gzip, xz and zstd compressed members.
+func TestOpen(t *testing.T) {
+	var paramTests = []struct {
+		params  string
+		pkg     string
+		files   int
+		scripts int
+	}{
+		{"../test_data/deb/com.yourcompany.tweakexample_0.0.1-2_iphoneos-arm.deb", "com.yourcompany.tweakexample", 6, 0},
+		{"../test_data/archive/com.example.compressed_2.0~beta1-3_iphoneos-arm64.xz.deb", "com.example.compressed", 4, 1},
+		{"../test_data/archive/com.example.compressed_2.0~beta1-3_iphoneos-arm64.zst.deb", "com.example.compressed", 4, 1},
+	}
+
+	for _, deb := range paramTests {
+		a, err := Open(deb.params)
+		if err != nil {
+			t.Errorf("Open(%q) failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", deb.params, nil, err)
+			continue
+		}
+		if a.FormatVersion() != "2.0" {
+			t.Errorf("Open(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", deb.params, "2.0", a.FormatVersion())
+		}
+		if a.Control().Package() != deb.pkg {
+			t.Errorf("Open(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", deb.params, deb.pkg, a.Control().Package())
+		}
+		if len(a.Files()) != deb.files {
+			t.Errorf("Open(%q) failed test. \n\n\rWant: \n\r\"%d\" files \n\rGot: \n\r\"%d\" files \n\n", deb.params, deb.files, len(a.Files()))
+		}
+		if len(a.Scripts()) != deb.scripts {
+			t.Errorf("Open(%q) failed test. \n\n\rWant: \n\r\"%d\" scripts \n\rGot: \n\r\"%d\" scripts \n\n", deb.params, deb.scripts, len(a.Scripts()))
+		}
+	}
+}
+
+// Testing reading a file which is not a deb.
+func TestOpenNotDeb(t *testing.T) {
+	_, err := Open("../test_data/packages/Packages")
+	if err == nil {
+		t.Errorf("Open(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", "Packages", "error", err)
+	}
+}
+
+// Testing multi-line, empty and unknown fields of a control file.
+func TestParseControl(t *testing.T) {
+	control := `# generated by theos
+Package: com.example.tweak
+Name: Tweak
+Depends:
+Pre-Depends: firmware (>= 14.0)
+Version: 1.0
+Architecture: iphoneos-arm64
+Installed-Size: 12
+Icon: file:///Library/Icon.png
+SileoDepiction: https://example.com/depiction.json
+Description: An awesome tweak!
+ It does many things.
+ .
+ And more.
+`
+	c, err := NewControl().ParseString(control)
+	if err != nil {
+		t.Fatalf("ParseString() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", nil, err)
+	}
+
+	var paramTests = []struct {
+		got  string
+		want string
+	}{
+		{c.Package(), "com.example.tweak"},
+		{c.Depends(), ""},
+		{c.Version(), "1.0"},
+		{c.Arch(), "iphoneos-arm64"},
+		{c.Homepage(), DefaultHomePage},
+		{c.Field("pre-depends"), "firmware (>= 14.0)"},
+		{c.Field("Icon"), "file:///Library/Icon.png"},
+		{c.Field("SileoDepiction"), "https://example.com/depiction.json"},
+		{c.Description(), "An awesome tweak!\nIt does many things.\n.\nAnd more."},
+	}
+	for _, p := range paramTests {
+		if p.got != p.want {
+			t.Errorf("ParseString() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", p.want, p.got)
+		}
+	}
+	if c.InstalledSize() != 12 {
+		t.Errorf("ParseString() failed test. \n\n\rWant: \n\r\"%d\" \n\rGot: \n\r\"%d\" \n\n", 12, c.InstalledSize())
+	}
+	if f := c.Paragraph().Fields(); len(f) != 10 || f[0].Name != "Package" || len(f[0].Comments) != 1 {
+		t.Errorf("ParseString() failed test. field order or comments were not preserved.")
+	}
+}
+
+// Testing line-numbered parse errors.
+func TestParseParagraphErrors(t *testing.T) {
+	var paramTests = []struct {
+		params string
+		line   int
+	}{
+		{"Package: foo\nPackage: bar\n", 2},
+		{" continuation\nPackage: foo\n", 1},
+		{"Package: foo\nVersion 1.0\n", 2},
+		{"Package: foo\n\nPackage: bar\n", 3},
+		{"", 0},
+	}
+
+	for _, p := range paramTests {
+		_, err := ParseParagraph(p.params)
+		perr, ok := err.(*ParseError)
+		if !ok || perr.Line != p.line {
+			t.Errorf("ParseParagraph(%q) failed test. \n\n\rWant: \n\r\"line %d\" \n\rGot: \n\r\"%v\" \n\n", p.params, p.line, err)
+		}
+	}
+}
+
+// Testing reading every entry of plain and compressed Packages files.
+func TestReadPackages(t *testing.T) {
+	packages, err := ioutil.ReadFile("../test_data/packages/Packages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Two entries of the same package with a different version.
+	second := bytes.Replace(packages, []byte("Version: 0.0.1-2"), []byte("Version: 0.0.2"), 1)
+	index := append(append([]byte{}, packages...), second...)
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(index)
+	zw.Close()
+
+	var paramTests = []struct {
+		name    string
+		params  []byte
+		entries int
+	}{
+		{"Packages", packages, 1},
+		{"Packages (2 entries)", index, 2},
+		{"Packages.gz", gz.Bytes(), 2},
+	}
+
+	for _, p := range paramTests {
+		entries, err := ReadPackages(bytes.NewReader(p.params))
+		if err != nil {
+			t.Errorf("ReadPackages(%q) failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", p.name, nil, err)
+			continue
+		}
+		if len(entries) != p.entries {
+			t.Errorf("ReadPackages(%q) failed test. \n\n\rWant: \n\r\"%d\" entries \n\rGot: \n\r\"%d\" entries \n\n", p.name, p.entries, len(entries))
+			continue
+		}
+		if entries[0].Package() != "com.yourcompany.tweakexample" || entries[0].Size() != 2166 {
+			t.Errorf("ReadPackages(%q) failed test. first entry was not parsed.", p.name)
+		}
+		if entries[len(entries)-1].Version() != "0.0.1-2" && entries[len(entries)-1].Version() != "0.0.2" {
+			t.Errorf("ReadPackages(%q) failed test. last entry was not parsed.", p.name)
+		}
+	}
+}
+
+// Testing writing a control file in canonical order after injecting a field.
+func TestControlMarshalText(t *testing.T) {
+	c, err := NewControl().ParseString("Name: Tweak\nDescription: An awesome tweak!\n It does things.\n .\n More.\nDepends:\nVersion: 1.0\nPackage: com.example.tweak\nIcon: file:///icon.png\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("Depiction", "https://example.com/depiction"); err != nil {
+		t.Fatal(err)
+	}
+	if c.Depiction() != "https://example.com/depiction" {
+		t.Errorf("Set() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", "https://example.com/depiction", c.Depiction())
+	}
+
+	want := `Package: com.example.tweak
+Version: 1.0
+Name: Tweak
+Icon: file:///icon.png
+Depiction: https://example.com/depiction
+Description: An awesome tweak!
+ It does things.
+ .
+ More.
+`
+	got, err := c.MarshalText()
+	if err != nil || string(got) != want {
+		t.Errorf("MarshalText() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", want, got)
+	}
+}
+
+// Testing a Packages file survives a read and write round trip.
+func TestWritePackages(t *testing.T) {
+	packages, err := ioutil.ReadFile("../test_data/packages/Packages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ReadPackages(bytes.NewReader(packages))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WritePackages(&buf, append(entries, entries...)); err != nil {
+		t.Fatal(err)
+	}
+	again, err := ReadPackages(&buf)
+	if err != nil || len(again) != 2 {
+		t.Fatalf("WritePackages() failed test. \n\n\rWant: \n\r\"%d\" entries \n\rGot: \n\r\"%d\" entries (%v) \n\n", 2, len(again), err)
+	}
+	for _, f := range entries[0].Paragraph().Fields() {
+		if again[1].Field(f.Name) != f.Value {
+			t.Errorf("WritePackages() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", f.Value, again[1].Field(f.Name))
+		}
+	}
+}
+
+// Testing dpkg version ordering.
+func TestCompareVersions(t *testing.T) {
+	var paramTests = []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.0-0", 0},
+		{"0.0.1-2", "0.0.2", -1},
+		{"1.10", "1.9", 1},
+		{"1.0~beta1", "1.0", -1},
+		{"1.0~beta1", "1.0~beta2", -1},
+		{"1.0~~", "1.0~", -1},
+		{"1.0a", "1.0", 1},
+		{"1.0a", "1.0+", -1},
+		{"1:0.1", "2.0", 1},
+		{"2.0-1", "2.0-10", -1},
+		{"1.01", "1.1", 0},
+		{"2.0~beta1-3", "2.0~beta1-2", 1},
+	}
+
+	for _, p := range paramTests {
+		got, err := CompareVersions(p.a, p.b)
+		if err != nil || got != p.want {
+			t.Errorf("CompareVersions(%q, %q) failed test. \n\n\rWant: \n\r\"%d\" \n\rGot: \n\r\"%d\" (%v) \n\n", p.a, p.b, p.want, got, err)
+		}
+	}
+}
+
+// Testing invalid versions are rejected.
+func TestParseVersionErrors(t *testing.T) {
+	for _, v := range []string{"", "a:1.0", "1.0-", "1.0 beta", "1.0_beta", ":1.0"} {
+		if _, err := ParseVersion(v); err == nil {
+			t.Errorf("ParseVersion(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", v, "error", err)
+		}
+	}
+}
+
+// Testing Depends expressions against available packages.
+func TestSatisfiesDepends(t *testing.T) {
+	available := map[string]string{
+		"mobilesubstrate":  "0.9.7113",
+		"firmware":         "14.4",
+		"preferenceloader": "2.2.6~beta-1",
+	}
+	var paramTests = []struct {
+		params string
+		want   bool
+	}{
+		{"", true},
+		{"mobilesubstrate", true},
+		{"mobilesubstrate (>= 0.9)", true},
+		{"mobilesubstrate (<< 0.9)", false},
+		{"firmware (>= 15.0) | mobilesubstrate (= 0.9.7113)", true},
+		{"firmware (>= 15.0) | ellekit", false},
+		{"mobilesubstrate, firmware (>> 14.0), preferenceloader (<< 2.2.6)", true},
+		{"mobilesubstrate, applist", false},
+		{"firmware (< 14.4)", true},
+	}
+
+	for _, p := range paramTests {
+		got, err := SatisfiesDepends(p.params, available)
+		if err != nil || got != p.want {
+			t.Errorf("SatisfiesDepends(%q) failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" (%v) \n\n", p.params, p.want, got, err)
+		}
+	}
+}
+
+// Testing parsing relationship fields.
+func TestParseRelationship(t *testing.T) {
+	var paramTests = []struct {
+		params string
+		want   string
+		groups int
+	}{
+		{"", "", 0},
+		{"mobilesubstrate", "mobilesubstrate", 1},
+		{"mobilesubstrate (>=0.9), preferenceloader", "mobilesubstrate (>= 0.9), preferenceloader", 2},
+		{"firmware (>= 14.0) |\n ellekit:any [iphoneos-arm64 !iphoneos-arm]", "firmware (>= 14.0) | ellekit:any [iphoneos-arm64 !iphoneos-arm]", 1},
+	}
+
+	for _, p := range paramTests {
+		rel, err := ParseRelationship(p.params)
+		if err != nil || rel.String() != p.want || len(rel) != p.groups {
+			t.Errorf("ParseRelationship(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" (%v) \n\n", p.params, p.want, rel, err)
+		}
+	}
+
+	for _, bad := range []string{"foo (>= 1.0", "foo (~ 1.0)", "foo bar", "foo, | bar", "foo:"} {
+		if _, err := ParseRelationship(bad); err == nil {
+			t.Errorf("ParseRelationship(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", bad, "error", err)
+		}
+	}
+
+	c, err := NewControl().ParseString("Package: com.example.tweak\nVersion: 1.0\nConflicts: com.example.old (<< 1.0)\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rel, err := c.Relations("Conflicts")
+	if err != nil || len(rel) != 1 || rel[0][0].Name != "com.example.old" || !rel[0].Match("com.example.old", "0.9") || rel[0].Match("com.example.old", "1.0") {
+		t.Errorf("Relations(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" (%v) \n\n", "Conflicts", "com.example.old (<< 1.0)", rel, err)
+	}
+}
@@ -0,0 +1,102 @@
+package deb
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// PackagesReader reads the entries of a Packages file one at a time.
+type PackagesReader struct {
+	pr    *paragraphReader
+	close func()
+}
+
+// NewPackagesReader creates a PackagesReader reading from r.
+// Packages files compressed with bzip2, gzip, xz or zstd are detected and decompressed.
+// The reader must be closed, to release the decompressor.
+func NewPackagesReader(r io.Reader) (*PackagesReader, error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(6)
+
+	var dr io.Reader = br
+	var err error
+	close := func() {}
+	switch {
+	case bytes.HasPrefix(magic, []byte("BZh")):
+		dr = bzip2.NewReader(br)
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		dr, err = gzip.NewReader(br)
+	case bytes.HasPrefix(magic, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}):
+		dr, err = xz.NewReader(br)
+	case bytes.HasPrefix(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		var zr *zstd.Decoder
+		zr, err = zstd.NewReader(br)
+		if err == nil {
+			dr, close = zr, zr.Close
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &PackagesReader{pr: newParagraphReader(dr), close: close}, nil
+}
+
+// Close releases the decompressor of the reader. It does not close the underlying reader.
+func (r *PackagesReader) Close() error {
+	r.close()
+	return nil
+}
+
+// Next returns the next entry of the Packages file. It returns io.EOF when there are no more entries.
+func (r *PackagesReader) Next() (*Packages, error) {
+	para, err := r.pr.next()
+	if err != nil {
+		return nil, err
+	}
+	if para == nil {
+		return nil, io.EOF
+	}
+	pkgs, err := packagesFromParagraph(para)
+	if err != nil {
+		return nil, &ParseError{Line: para.fields[0].Line, Msg: "entry " + strconv.Quote(para.Get("Package")) + ": " + err.Error()}
+	}
+	return pkgs, nil
+}
+
+// ReadPackages reads every entry of the Packages file r.
+func ReadPackages(r io.Reader) ([]*Packages, error) {
+	pr, err := NewPackagesReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer pr.Close()
+	var entries []*Packages
+	for {
+		p, err := pr.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, p)
+	}
+}
+
+// OpenPackages reads every entry of the Packages file at path. (Packages, Packages.bz2, Packages.xz...)
+func OpenPackages(path string) ([]*Packages, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadPackages(f)
+}
@@ -0,0 +1,190 @@
+package deb
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Field represents a single field of a deb822 paragraph. (Package: com.example.tweakexample)
+// Multi-line values keep their continuation lines, without the leading space, separated by newlines.
+type Field struct {
+	Name     string
+	Value    string
+	Line     int
+	Comments []string
+}
+
+// Paragraph represents a deb822 paragraph such as a control file or a single Packages entry.
+// Fields keep the order they were parsed or set in.
+type Paragraph struct {
+	fields   []Field
+	comments []string
+}
+
+// ParseError represents a syntax error in a control or Packages file.
+type ParseError struct {
+	Line int
+	Msg  string
+}
+
+// Error returns the parse error with its line number.
+func (e *ParseError) Error() string {
+	return "line " + strconv.Itoa(e.Line) + ": " + e.Msg
+}
+
+// NewParagraph creates a new empty Paragraph.
+func NewParagraph() *Paragraph {
+	return &Paragraph{}
+}
+
+// ParseParagraph parses a single deb822 paragraph from the string f.
+func ParseParagraph(f string) (*Paragraph, error) {
+	pr := newParagraphReader(strings.NewReader(f))
+	p, err := pr.next()
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, &ParseError{Line: pr.line, Msg: "no fields found"}
+	}
+	extra, err := pr.next()
+	if err != nil {
+		return nil, err
+	}
+	if extra != nil {
+		return nil, &ParseError{Line: extra.fields[0].Line, Msg: "unexpected second paragraph"}
+	}
+	return p, nil
+}
+
+// Fields returns the fields of the paragraph in order.
+func (p *Paragraph) Fields() []Field {
+	return p.fields
+}
+
+// Len returns the number of fields in the paragraph.
+func (p *Paragraph) Len() int {
+	return len(p.fields)
+}
+
+// Comments returns the comment lines following the last field of the paragraph.
+func (p *Paragraph) Comments() []string {
+	return p.comments
+}
+
+// Lookup returns the value of the field name and whether it exists. Field names are case-insensitive.
+func (p *Paragraph) Lookup(name string) (string, bool) {
+	if i := p.index(name); i >= 0 {
+		return p.fields[i].Value, true
+	}
+	return "", false
+}
+
+// Get returns the value of the field name or an empty string.
+func (p *Paragraph) Get(name string) string {
+	v, _ := p.Lookup(name)
+	return v
+}
+
+// Set sets the value of the field name, appending it when it does not exist.
+func (p *Paragraph) Set(name string, value string) {
+	if i := p.index(name); i >= 0 {
+		p.fields[i].Value = value
+		return
+	}
+	p.fields = append(p.fields, Field{Name: name, Value: value})
+}
+
+// Delete removes the field name from the paragraph.
+func (p *Paragraph) Delete(name string) {
+	if i := p.index(name); i >= 0 {
+		p.fields = append(p.fields[:i], p.fields[i+1:]...)
+	}
+}
+
+// index returns the position of the field name or -1.
+func (p *Paragraph) index(name string) int {
+	for i, f := range p.fields {
+		if strings.EqualFold(f.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// paragraphReader reads deb822 paragraphs line by line.
+type paragraphReader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+// newParagraphReader creates a paragraphReader reading from r.
+func newParagraphReader(r io.Reader) *paragraphReader {
+	s := bufio.NewScanner(r)
+	// Descriptions and Conffiles can have very long lines.
+	s.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	return &paragraphReader{s: s}
+}
+
+// next returns the next paragraph, or nil when there are no more paragraphs.
+func (pr *paragraphReader) next() (*Paragraph, error) {
+	var p *Paragraph
+	var comments []string
+	for pr.s.Scan() {
+		pr.line++
+		line := strings.TrimRight(pr.s.Text(), "\r")
+
+		// Blank lines separate paragraphs.
+		if strings.TrimSpace(line) == "" {
+			if p != nil {
+				p.comments = comments
+				return p, nil
+			}
+			continue
+		}
+		if line[0] == '#' {
+			comments = append(comments, line)
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if p == nil {
+				return nil, &ParseError{Line: pr.line, Msg: "continuation line outside of a field"}
+			}
+			// The first whitespace character only marks the continuation.
+			f := &p.fields[len(p.fields)-1]
+			f.Value += "\n" + strings.TrimRight(line[1:], " \t")
+			continue
+		}
+
+		i := strings.Index(line, ":")
+		if i < 0 {
+			return nil, &ParseError{Line: pr.line, Msg: "missing colon in field \"" + line + "\""}
+		}
+		name := line[:i]
+		if name == "" || name[0] == '-' || strings.ContainsAny(name, " \t") {
+			return nil, &ParseError{Line: pr.line, Msg: "invalid field name \"" + name + "\""}
+		}
+		if p == nil {
+			p = NewParagraph()
+		}
+		if j := p.index(name); j >= 0 {
+			return nil, &ParseError{Line: pr.line, Msg: "duplicate field \"" + name + "\" (first defined on line " + strconv.Itoa(p.fields[j].Line) + ")"}
+		}
+		p.fields = append(p.fields, Field{
+			Name:     name,
+			Value:    strings.TrimSpace(line[i+1:]),
+			Line:     pr.line,
+			Comments: comments,
+		})
+		comments = nil
+	}
+	if err := pr.s.Err(); err != nil {
+		return nil, err
+	}
+	if p != nil {
+		p.comments = comments
+	}
+	return p, nil
+}
@@ -0,0 +1,180 @@
+package deb
+
+import (
+	"errors"
+	"strings"
+)
+
+// Relation represents a single package of a relationship field. (mobilesubstrate (>= 0.9))
+// Arch is the architecture qualifier of the package (foo:any) and Archs the
+// architecture restriction list. (foo [iphoneos-arm64 !iphoneos-arm])
+type Relation struct {
+	Name       string
+	Arch       string
+	Constraint *Constraint
+	Archs      []string
+}
+
+// Alternatives represents a group of relations of which any one is enough. (firmware (>= 14.0) | ellekit)
+type Alternatives []Relation
+
+// Relationship represents a parsed relationship field such as Depends, Conflicts or Provides.
+// Every group of alternatives in it must be satisfied.
+type Relationship []Alternatives
+
+// RelationshipFields are the control fields holding relationships between packages.
+var RelationshipFields = []string{"Pre-Depends", "Depends", "Recommends", "Suggests", "Enhances", "Conflicts", "Breaks", "Replaces", "Provides"}
+
+// ParseRelationship parses the value of a relationship field. (mobilesubstrate (>= 0.9), preferenceloader)
+func ParseRelationship(s string) (Relationship, error) {
+	var rel Relationship
+	for _, clause := range strings.Split(s, ",") {
+		if strings.TrimSpace(clause) == "" {
+			continue
+		}
+		var alts Alternatives
+		for _, alt := range strings.Split(clause, "|") {
+			r, err := parseRelation(alt)
+			if err != nil {
+				return nil, err
+			}
+			alts = append(alts, r)
+		}
+		rel = append(rel, alts)
+	}
+	return rel, nil
+}
+
+// parseRelation parses a single package of a relationship field. (foo:any (>= 1.0) [iphoneos-arm])
+func parseRelation(s string) (Relation, error) {
+	var r Relation
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "" {
+		return r, errors.New("empty package name in relationship")
+	}
+
+	end := strings.IndexAny(s, " ([")
+	if end < 0 {
+		end = len(s)
+	}
+	r.Name, s = s[:end], strings.TrimSpace(s[end:])
+	if i := strings.Index(r.Name, ":"); i >= 0 {
+		r.Name, r.Arch = r.Name[:i], r.Name[i+1:]
+		if r.Arch == "" {
+			return r, errors.New("empty architecture qualifier for package \"" + r.Name + "\"")
+		}
+	}
+	if r.Name == "" || strings.ContainsAny(r.Name, ")]<>") {
+		return r, errors.New("invalid package name \"" + r.Name + "\" in relationship")
+	}
+
+	if strings.HasPrefix(s, "(") {
+		end := strings.Index(s, ")")
+		if end < 0 {
+			return r, errors.New("unclosed version constraint for package \"" + r.Name + "\"")
+		}
+		c, err := ParseConstraint(s[:end+1])
+		if err != nil {
+			return r, errors.New(r.Name + ": " + err.Error())
+		}
+		r.Constraint = &c
+		s = strings.TrimSpace(s[end+1:])
+	}
+
+	if strings.HasPrefix(s, "[") {
+		end := strings.Index(s, "]")
+		if end < 0 {
+			return r, errors.New("unclosed architecture list for package \"" + r.Name + "\"")
+		}
+		r.Archs = strings.Fields(s[1:end])
+		s = strings.TrimSpace(s[end+1:])
+	}
+
+	if s != "" {
+		return r, errors.New("unexpected \"" + s + "\" after package \"" + r.Name + "\"")
+	}
+	return r, nil
+}
+
+// String returns the relation in its debian form. (foo:any (>= 1.0) [iphoneos-arm])
+func (r Relation) String() string {
+	s := r.Name
+	if r.Arch != "" {
+		s += ":" + r.Arch
+	}
+	if r.Constraint != nil {
+		s += " (" + r.Constraint.String() + ")"
+	}
+	if len(r.Archs) > 0 {
+		s += " [" + strings.Join(r.Archs, " ") + "]"
+	}
+	return s
+}
+
+// String returns the alternatives in their debian form. (firmware (>= 14.0) | ellekit)
+func (a Alternatives) String() string {
+	s := make([]string, len(a))
+	for i, r := range a {
+		s[i] = r.String()
+	}
+	return strings.Join(s, " | ")
+}
+
+// String returns the relationship in its debian form. (mobilesubstrate (>= 0.9), preferenceloader)
+func (rel Relationship) String() string {
+	s := make([]string, len(rel))
+	for i, a := range rel {
+		s[i] = a.String()
+	}
+	return strings.Join(s, ", ")
+}
+
+// Names returns the name of every package mentioned in the relationship.
+func (rel Relationship) Names() []string {
+	var names []string
+	for _, a := range rel {
+		for _, r := range a {
+			names = append(names, r.Name)
+		}
+	}
+	return names
+}
+
+// Match returns whether the package name at version satisfies the relation.
+// An empty version only satisfies relations without a version constraint. (an unversioned Provides)
+func (r Relation) Match(name string, version string) bool {
+	if r.Name != name {
+		return false
+	}
+	if r.Constraint == nil {
+		return true
+	}
+	if version == "" {
+		return false
+	}
+	v, err := ParseVersion(version)
+	if err != nil {
+		return false
+	}
+	return r.Constraint.Match(v)
+}
+
+// Match returns whether the package name at version satisfies any of the alternatives.
+func (a Alternatives) Match(name string, version string) bool {
+	for _, r := range a {
+		if r.Match(name, version) {
+			return true
+		}
+	}
+	return false
+}
+
+// Relations returns the parsed relationship field name of the control file. (Depends, Conflicts, Provides...)
+func (c *Control) Relations(name string) (Relationship, error) {
+	return ParseRelationship(c.Field(name))
+}
+
+// Relations returns the parsed relationship field name of the Packages entry. (Depends, Conflicts, Provides...)
+func (p *Packages) Relations(name string) (Relationship, error) {
+	return ParseRelationship(p.Field(name))
+}
@@ -0,0 +1,247 @@
+package deb
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Version represents a debian package version. ([epoch:]upstream[-revision])
+type Version struct {
+	Epoch    int
+	Upstream string
+	Revision string
+}
+
+// ParseVersion parses a debian package version string. (1:0.0.1~beta-2)
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return v, errors.New("version string is empty")
+	}
+	if strings.ContainsAny(s, " \t") {
+		return v, errors.New("version string \"" + s + "\" has embedded spaces")
+	}
+
+	if i := strings.Index(s, ":"); i >= 0 {
+		epoch, err := strconv.Atoi(s[:i])
+		if err != nil || epoch < 0 {
+			return v, errors.New("epoch in version \"" + s + "\" is not a number")
+		}
+		v.Epoch = epoch
+		s = s[i+1:]
+	}
+	if i := strings.LastIndex(s, "-"); i >= 0 {
+		v.Revision = s[i+1:]
+		s = s[:i]
+		if v.Revision == "" {
+			return v, errors.New("revision in version \"" + s + "-\" is empty")
+		}
+	}
+	if s == "" {
+		return v, errors.New("upstream version is empty")
+	}
+	v.Upstream = s
+
+	for _, c := range v.Upstream + v.Revision {
+		if !isVersionChar(c) {
+			return v, errors.New("invalid character '" + string(c) + "' in version \"" + v.String() + "\"")
+		}
+	}
+	return v, nil
+}
+
+// isVersionChar returns whether c may appear in an upstream version or revision.
+func isVersionChar(c rune) bool {
+	return isDigit(c) || isAlpha(c) || strings.ContainsRune(".+~-:", c)
+}
+
+// String returns the version in its debian form. (1:0.0.1~beta-2)
+func (v Version) String() string {
+	s := v.Upstream
+	if v.Epoch > 0 {
+		s = strconv.Itoa(v.Epoch) + ":" + s
+	}
+	if v.Revision != "" {
+		s += "-" + v.Revision
+	}
+	return s
+}
+
+// Compare compares v to o using dpkg's ordering.
+// It returns -1 if v is older than o, 0 if they are equal and 1 if v is newer.
+func (v Version) Compare(o Version) int {
+	if v.Epoch != o.Epoch {
+		if v.Epoch < o.Epoch {
+			return -1
+		}
+		return 1
+	}
+	if c := compareFragment(v.Upstream, o.Upstream); c != 0 {
+		return c
+	}
+	return compareFragment(v.Revision, o.Revision)
+}
+
+// CompareVersions parses and compares the version strings a and b. (see Version.Compare)
+func CompareVersions(a string, b string) (int, error) {
+	va, err := ParseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := ParseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	return va.Compare(vb), nil
+}
+
+// compareFragment compares an upstream version or revision the way dpkg's verrevcmp does.
+// Non-digit parts compare letters before other characters, with '~' sorting before everything,
+// even the end of the string. Digit parts compare numerically.
+func compareFragment(a string, b string) int {
+	for a != "" || b != "" {
+		for (a != "" && !isDigit(rune(a[0]))) || (b != "" && !isDigit(rune(b[0]))) {
+			ac, bc := charOrder(a), charOrder(b)
+			if ac != bc {
+				return sign(ac - bc)
+			}
+			a, b = a[1:], b[1:]
+		}
+
+		a = strings.TrimLeft(a, "0")
+		b = strings.TrimLeft(b, "0")
+		firstDiff := 0
+		for a != "" && isDigit(rune(a[0])) && b != "" && isDigit(rune(b[0])) {
+			if firstDiff == 0 {
+				firstDiff = int(a[0]) - int(b[0])
+			}
+			a, b = a[1:], b[1:]
+		}
+		if a != "" && isDigit(rune(a[0])) {
+			return 1
+		}
+		if b != "" && isDigit(rune(b[0])) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return sign(firstDiff)
+		}
+	}
+	return 0
+}
+
+// charOrder returns the sort weight of the first character of s.
+func charOrder(s string) int {
+	if s == "" {
+		return 0
+	}
+	c := rune(s[0])
+	switch {
+	case isDigit(c):
+		return 0
+	case isAlpha(c):
+		return int(c)
+	case c == '~':
+		return -1
+	default:
+		return int(c) + 256
+	}
+}
+
+func isDigit(c rune) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isAlpha(c rune) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// Constraint represents a version constraint of a dependency. (>= 1.0)
+type Constraint struct {
+	Op      string
+	Version Version
+}
+
+// constraintOps are the version relations dpkg understands, longest first.
+// The obsolete '<' and '>' mean '<=' and '>='.
+var constraintOps = []string{"<<", "<=", ">=", ">>", "=", "<", ">"}
+
+// ParseConstraint parses a version constraint with or without parentheses. ((>= 1.0) or >= 1.0)
+func ParseConstraint(s string) (Constraint, error) {
+	var c Constraint
+	s = strings.TrimSpace(s)
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(s, "("), ")"))
+	for _, op := range constraintOps {
+		if strings.HasPrefix(s, op) {
+			c.Op = op
+			break
+		}
+	}
+	if c.Op == "" {
+		return c, errors.New("version constraint \"" + s + "\" has no relation")
+	}
+	v, err := ParseVersion(strings.TrimPrefix(s, c.Op))
+	if err != nil {
+		return c, err
+	}
+	c.Version = v
+	return c, nil
+}
+
+// String returns the constraint in its debian form. (>= 1.0)
+func (c Constraint) String() string {
+	return c.Op + " " + c.Version.String()
+}
+
+// Match returns whether the version v satisfies the constraint.
+func (c Constraint) Match(v Version) bool {
+	cmp := v.Compare(c.Version)
+	switch c.Op {
+	case "<<":
+		return cmp < 0
+	case "<=", "<":
+		return cmp <= 0
+	case "=":
+		return cmp == 0
+	case ">=", ">":
+		return cmp >= 0
+	case ">>":
+		return cmp > 0
+	}
+	return false
+}
+
+// SatisfiesDepends returns whether the packages in available, mapping package names to versions,
+// satisfy the Depends expression. (mobilesubstrate (>= 0.9), firmware (>= 14.0) | ellekit)
+// Every comma separated clause must be satisfied by at least one of its '|' alternatives.
+func SatisfiesDepends(expr string, available map[string]string) (bool, error) {
+	rel, err := ParseRelationship(expr)
+	if err != nil {
+		return false, err
+	}
+	for _, alts := range rel {
+		satisfied := false
+		for _, r := range alts {
+			if version, ok := available[r.Name]; ok && r.Match(r.Name, version) {
+				satisfied = true
+				break
+			}
+		}
+		if !satisfied {
+			return false, nil
+		}
+	}
+	return true, nil
+}
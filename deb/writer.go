@@ -0,0 +1,158 @@
+package deb
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// fieldOrder is the canonical order of known control and Packages fields.
+// Fields not listed here keep their original order after the known fields, and Description is always last.
+var fieldOrder = []string{
+	"Package", "Source", "Version", "Architecture", "Essential", "Maintainer", "Installed-Size",
+	"Pre-Depends", "Depends", "Recommends", "Suggests", "Enhances", "Conflicts", "Breaks",
+	"Replaces", "Provides", "Filename", "Size", "MD5sum", "SHA1", "SHA256", "Section",
+	"Priority", "Homepage",
+}
+
+// WriteTo writes the paragraph to w in deb822 format, keeping the order of its fields.
+// Empty fields are omitted and multi-line values are folded into continuation lines.
+func (p *Paragraph) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	for _, f := range p.fields {
+		writeField(&buf, f)
+	}
+	return buf.WriteTo(w)
+}
+
+// String returns the paragraph in deb822 format.
+func (p *Paragraph) String() string {
+	var buf bytes.Buffer
+	p.WriteTo(&buf)
+	return buf.String()
+}
+
+// Canonical returns a copy of the paragraph with its fields in canonical order. (Package first, Description last)
+func (p *Paragraph) Canonical() *Paragraph {
+	c := &Paragraph{comments: p.comments}
+	for _, name := range fieldOrder {
+		if i := p.index(name); i >= 0 {
+			c.fields = append(c.fields, p.fields[i])
+		}
+	}
+	for _, f := range p.fields {
+		if c.index(f.Name) < 0 && !strings.EqualFold(f.Name, "Description") {
+			c.fields = append(c.fields, f)
+		}
+	}
+	if i := p.index("Description"); i >= 0 {
+		c.fields = append(c.fields, p.fields[i])
+	}
+	return c
+}
+
+// writeField writes a single field, escaping empty continuation lines with a dot.
+func writeField(buf *bytes.Buffer, f Field) {
+	if strings.TrimSpace(f.Value) == "" {
+		return
+	}
+	lines := strings.Split(f.Value, "\n")
+	buf.WriteString(f.Name + ":")
+	if lines[0] != "" {
+		buf.WriteString(" " + strings.TrimSpace(lines[0]))
+	}
+	buf.WriteString("\n")
+	for _, l := range lines[1:] {
+		l = strings.TrimRight(l, " \t")
+		if l == "" {
+			l = "."
+		}
+		buf.WriteString(" " + l + "\n")
+	}
+}
+
+// MarshalText returns the control file in deb822 format with its fields in canonical order.
+func (c *Control) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteTo writes the control file to w in deb822 format with its fields in canonical order.
+func (c *Control) WriteTo(w io.Writer) (int64, error) {
+	if c.paragraph == nil {
+		return 0, nil
+	}
+	return c.paragraph.Canonical().WriteTo(w)
+}
+
+// MarshalText returns the Packages entry in deb822 format with its fields in canonical order.
+func (p *Packages) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := p.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteTo writes the Packages entry to w in deb822 format with its fields in canonical order.
+// The entry is terminated by a blank line so that entries can be written one after another.
+func (p *Packages) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	if p.paragraph != nil {
+		p.paragraph.Canonical().WriteTo(&buf)
+	}
+	buf.WriteString("\n")
+	return buf.WriteTo(w)
+}
+
+// WritePackages writes every entry to w, forming a Packages file.
+func WritePackages(w io.Writer, entries []*Packages) error {
+	for _, p := range entries {
+		if _, err := p.WriteTo(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Set sets the control field name to value, adding it when it does not exist. (Depiction)
+func (c *Control) Set(name string, value string) error {
+	para := c.paragraph
+	if para == nil {
+		para = NewParagraph()
+	}
+	para = para.copy()
+	para.Set(name, value)
+	nc, err := controlFromParagraph(para)
+	if err != nil {
+		return err
+	}
+	*c = *nc
+	return nil
+}
+
+// Set sets the Packages field name to value, adding it when it does not exist. (Filename)
+func (p *Packages) Set(name string, value string) error {
+	para := p.paragraph
+	if para == nil {
+		para = NewParagraph()
+	}
+	para = para.copy()
+	para.Set(name, value)
+	np, err := packagesFromParagraph(para)
+	if err != nil {
+		return err
+	}
+	*p = *np
+	return nil
+}
+
+// copy returns a copy of the paragraph which can be changed independently.
+func (p *Paragraph) copy() *Paragraph {
+	c := &Paragraph{comments: p.comments}
+	c.fields = append(c.fields, p.fields...)
+	return c
+}
@@ -1,11 +1,23 @@
 module github.com/hako/afto
 
-go 1.12
+go 1.23.0
 
 require (
+	github.com/ProtonMail/go-crypto v1.5.2
 	github.com/docopt/docopt-go v0.0.0-20180111231733-ee0de3bc6815
+	github.com/dsnet/compress v0.0.1
 	github.com/fatih/color v1.7.0
 	github.com/gorilla/handlers v1.4.0
-	github.com/mattn/go-colorable v0.1.2 // indirect
+	github.com/klauspost/compress v1.11.13
 	github.com/rjeczalik/notify v0.9.2
+	github.com/ulikunitz/xz v0.5.12
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/cloudflare/circl v1.6.3 // indirect
+	github.com/mattn/go-colorable v0.1.2 // indirect
+	github.com/mattn/go-isatty v0.0.8 // indirect
+	golang.org/x/crypto v0.41.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
 )
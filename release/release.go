@@ -3,34 +3,66 @@ package release
 
 import (
 	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
 	"fmt"
+	"hash"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hako/afto/deb"
 )
 
+// DateFormat is the format of the Date and Valid-Until fields. (Sat, 17 Oct 2026 12:00:00 UTC)
+const DateFormat = "Mon, 02 Jan 2006 15:04:05 MST"
+
 // Release represents a structure of a repo Release file.
 type Release struct {
-	origin      string
-	label       string
-	suite       string
-	version     int
-	codename    string
-	arch        string
-	components  string
-	description string
-	signatures  []MD5Signature
+	origin        string
+	label         string
+	suite         string
+	version       int
+	codename      string
+	date          time.Time
+	validUntil    time.Time
+	arch          string
+	components    string
+	description   string
+	notAutomatic  bool
+	acquireByHash bool
+	files         []IndexFile
+	extra         []deb.Field
+}
+
+// IndexFile represents an index file (Packages, Packages.bz2...) listed in a repo Release file.
+type IndexFile struct {
+	Name   string
+	Size   int
+	MD5    string
+	SHA1   string
+	SHA256 string
+	SHA512 string
 }
 
 // MD5Signature represents a signed repo Release file.
+// Deprecated: the Release file lists every hash of an index file, use IndexFile.
 type MD5Signature struct {
 	sum         string
 	size        int
 	packageName string
 }
 
+// HashFields are the Release file fields listing the index file hashes, from weakest to strongest.
+var HashFields = []string{"MD5Sum", "SHA1", "SHA256", "SHA512"}
+
 // NewRelease creates a new Release struct for a Release file with default values.
 func NewRelease() *Release {
 	return &Release{
 		suite:      "beta",
+		date:       time.Now().UTC(),
 		arch:       "iphoneos-arm",
 		components: "main",
 	}
@@ -61,6 +93,16 @@ func (r Release) Codename() string {
 	return r.codename
 }
 
+// Date returns the time the Release file was generated.
+func (r Release) Date() time.Time {
+	return r.date
+}
+
+// ValidUntil returns the time after which the Release file is expired, or the zero time when it never expires.
+func (r Release) ValidUntil() time.Time {
+	return r.validUntil
+}
+
 // Arch returns repo with all the supported architectures. (iphoneos-arm for 2.x or darwin-arm or 1.1.x)
 func (r Release) Arch() string {
 	return r.arch
@@ -76,6 +118,31 @@ func (r Release) Description() string {
 	return r.description
 }
 
+// NotAutomatic returns whether package managers should not install packages from the repo automatically.
+func (r Release) NotAutomatic() bool {
+	return r.notAutomatic
+}
+
+// AcquireByHash returns whether the index files can be fetched by their hash. (by-hash/SHA256/<hash>)
+func (r Release) AcquireByHash() bool {
+	return r.acquireByHash
+}
+
+// Files returns the index files listed in the Release file.
+func (r Release) Files() []IndexFile {
+	return r.files
+}
+
+// Field returns the value of a field without an accessor. (Changelogs, Signed-By...)
+func (r Release) Field(name string) string {
+	for _, f := range r.extra {
+		if strings.EqualFold(f.Name, name) {
+			return f.Value
+		}
+	}
+	return ""
+}
+
 // SetOrigin sets the name of the Cydia repository.
 func (r *Release) SetOrigin(origin string) {
 	r.origin = origin
@@ -92,7 +159,7 @@ func (r *Release) SetSuite(suite string) {
 }
 
 // SetVersion sets the version number of the repo. (saruik questioned it's purpose...)
-func (r Release) SetVersion(version int) {
+func (r *Release) SetVersion(version int) {
 	r.version = version
 }
 
@@ -101,6 +168,16 @@ func (r *Release) SetCodename(codename string) {
 	r.codename = codename
 }
 
+// SetDate sets the time the Release file was generated. (now by default)
+func (r *Release) SetDate(date time.Time) {
+	r.date = date.UTC()
+}
+
+// SetValidUntil sets the time after which the Release file is expired. The zero time never expires.
+func (r *Release) SetValidUntil(validUntil time.Time) {
+	r.validUntil = validUntil.UTC()
+}
+
 // SetArch sets repo with all the supported architectures. (iphoneos-arm for 2.x or darwin-arm or 1.1.x)
 func (r *Release) SetArch(arch string) {
 	r.arch = arch
@@ -116,39 +193,219 @@ func (r *Release) SetDescription(desc string) {
 	r.description = desc
 }
 
-// AddPackageSignature appends an MD5 Signature of Packages & Packages.bz2 in the Release file.
+// SetNotAutomatic sets whether package managers should not install packages from the repo automatically.
+func (r *Release) SetNotAutomatic(notAutomatic bool) {
+	r.notAutomatic = notAutomatic
+}
+
+// SetAcquireByHash sets whether the index files can be fetched by their hash. (by-hash/SHA256/<hash>)
+func (r *Release) SetAcquireByHash(acquireByHash bool) {
+	r.acquireByHash = acquireByHash
+}
+
+// AddIndexFile hashes an index file (Packages, Packages.bz2, Packages.xz...) with every
+// supported algorithm and lists it in the Release file.
 // It should be in the form of:
 // MD5Sum:
 //  <hash> <size in bytes> Packages
 //  <hash> <size in bytes> Packages.bz2
-func (r *Release) AddPackageSignature(pkgs []byte, pkgbz2 []byte) {
-	pkgsum := fmt.Sprintf("%x", md5.Sum(pkgs))
-	pkgbz2sum := fmt.Sprintf("%x", md5.Sum(pkgbz2))
+// SHA1:
+//  ...
+func (r *Release) AddIndexFile(name string, data []byte) {
+	r.files = append(r.files, IndexFile{
+		Name:   name,
+		Size:   len(data),
+		MD5:    sum(md5.New(), data),
+		SHA1:   sum(sha1.New(), data),
+		SHA256: sum(sha256.New(), data),
+		SHA512: sum(sha512.New(), data),
+	})
+}
 
-	pkgsig := MD5Signature{pkgsum, len(pkgs), "Packages"}
-	pkgbz2sig := MD5Signature{pkgbz2sum, len(pkgbz2), "Packages.bz2"}
+// AddPackageSignature appends the signatures of a Packages file (Packages, Packages.bz2, Packages.xz...) in the Release file.
+// Call it once for every compressed variant.
+// Deprecated: AddPackageSignature lists the file with every hash, as AddIndexFile does, use AddIndexFile.
+func (r *Release) AddPackageSignature(name string, data []byte) {
+	r.AddIndexFile(name, data)
+}
+
+// sum returns the hex encoded hash of data.
+func sum(h hash.Hash, data []byte) string {
+	h.Write(data)
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
 
-	r.signatures = []MD5Signature{pkgsig, pkgbz2sig}
+// Hash returns the hash of the index file for one of the HashFields. (SHA256)
+func (f IndexFile) Hash(field string) string {
+	switch strings.ToUpper(field) {
+	case "MD5SUM":
+		return f.MD5
+	case "SHA1":
+		return f.SHA1
+	case "SHA256":
+		return f.SHA256
+	case "SHA512":
+		return f.SHA512
+	}
+	return ""
+}
+
+// setHash sets the hash of the index file for one of the HashFields.
+func (f *IndexFile) setHash(field string, h string) {
+	switch strings.ToUpper(field) {
+	case "MD5SUM":
+		f.MD5 = h
+	case "SHA1":
+		f.SHA1 = h
+	case "SHA256":
+		f.SHA256 = h
+	case "SHA512":
+		f.SHA512 = h
+	}
 }
 
 // Generate creates a release file from the Release struct.
-// It appends a signature at the end of the release file.
+// It appends the hashes of every index file at the end of the release file.
 func (r Release) Generate() string {
+	p := deb.NewParagraph()
+	p.Set("Origin", r.origin)
+	p.Set("Label", r.label)
+	p.Set("Suite", r.suite)
+	p.Set("Version", strconv.Itoa(r.version))
+	p.Set("Codename", r.codename)
+	if !r.date.IsZero() {
+		p.Set("Date", r.date.Format(DateFormat))
+	}
+	if !r.validUntil.IsZero() {
+		p.Set("Valid-Until", r.validUntil.Format(DateFormat))
+	}
+	p.Set("Architectures", r.arch)
+	p.Set("Components", r.components)
+	p.Set("Description", r.description)
+	if r.notAutomatic {
+		p.Set("NotAutomatic", "yes")
+	}
+	if r.acquireByHash {
+		p.Set("Acquire-By-Hash", "yes")
+	}
+	for _, f := range r.extra {
+		p.Set(f.Name, f.Value)
+	}
 
-	var release = `Origin: ` + r.origin + `
-Label: ` + r.label + `
-Suite: ` + r.suite + `
-Version: ` + strconv.Itoa(r.version) + `
-Codename: ` + r.codename + `
-Architectures: ` + r.arch + `
-Components: ` + r.components + `
-Description: ` + r.description + `
-MD5Sum:
-`
+	for _, field := range HashFields {
+		var lines []string
+		for _, f := range r.files {
+			if h := f.Hash(field); h != "" {
+				lines = append(lines, fmt.Sprintf("%s %d %s", h, f.Size, f.Name))
+			}
+		}
+		if len(lines) > 0 {
+			p.Set(field, "\n"+strings.Join(lines, "\n"))
+		}
+	}
 
-	for _, s := range r.signatures {
-		release += fmt.Sprintf(" %s %d %s\n", s.sum, s.size, s.packageName)
+	return p.String()
+}
+
+// ParseString parses an existing Release file f and returns a *Release struct.
+// Fields without an accessor are kept and generated again.
+func (r *Release) ParseString(f string) (*Release, error) {
+	p, err := deb.ParseParagraph(f)
+	if err != nil {
+		return nil, err
+	}
+
+	rel := &Release{}
+	for _, field := range p.Fields() {
+		var err error
+		switch strings.ToLower(field.Name) {
+		case "origin":
+			rel.origin = field.Value
+		case "label":
+			rel.label = field.Value
+		case "suite":
+			rel.suite = field.Value
+		case "version":
+			// Versions which are not a number (12.5) are kept as they are.
+			v, verr := strconv.Atoi(field.Value)
+			if verr != nil {
+				rel.extra = append(rel.extra, field)
+			}
+			rel.version = v
+		case "codename":
+			rel.codename = field.Value
+		case "date":
+			rel.date, err = parseDate(field.Value)
+		case "valid-until":
+			rel.validUntil, err = parseDate(field.Value)
+		case "architectures":
+			rel.arch = field.Value
+		case "components":
+			rel.components = field.Value
+		case "description":
+			rel.description = field.Value
+		case "notautomatic":
+			rel.notAutomatic = field.Value == "yes"
+		case "acquire-by-hash":
+			rel.acquireByHash = field.Value == "yes"
+		case "md5sum", "sha1", "sha256", "sha512":
+			err = rel.parseHashes(field.Name, field.Value)
+		default:
+			rel.extra = append(rel.extra, field)
+		}
+		if err != nil {
+			return nil, &deb.ParseError{Line: field.Line, Msg: field.Name + ": " + err.Error()}
+		}
 	}
+	return rel, nil
+}
 
-	return release
+// parseDate parses the Date and Valid-Until fields, which may use a numeric zone. (+0000)
+func parseDate(s string) (time.Time, error) {
+	t, err := time.Parse(DateFormat, s)
+	if err != nil {
+		t, err = time.Parse(time.RFC1123Z, s)
+	}
+	if err != nil {
+		return t, errors.New("invalid date \"" + s + "\"")
+	}
+	return t.UTC(), nil
+}
+
+// parseHashes adds the hashes of a hash field to the index files, in the order they are listed.
+func (r *Release) parseHashes(field string, value string) error {
+	for _, line := range strings.Split(value, "\n") {
+		parts := strings.Fields(line)
+		if len(parts) == 0 {
+			continue
+		}
+		if len(parts) != 3 {
+			return errors.New("malformed line \"" + line + "\"")
+		}
+		size, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return errors.New("invalid size \"" + parts[1] + "\"")
+		}
+
+		i := r.indexFile(parts[2])
+		if i < 0 {
+			r.files = append(r.files, IndexFile{Name: parts[2], Size: size})
+			i = len(r.files) - 1
+		}
+		if r.files[i].Size != size {
+			return errors.New("size of \"" + parts[2] + "\" differs from an earlier hash")
+		}
+		r.files[i].setHash(field, parts[0])
+	}
+	return nil
+}
+
+// indexFile returns the position of the index file name or -1.
+func (r *Release) indexFile(name string) int {
+	for i, f := range r.files {
+		if f.Name == name {
+			return i
+		}
+	}
+	return -1
 }
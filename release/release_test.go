@@ -1 +1,103 @@
 package release
+
+import (
+	"testing"
+	"time"
+)
+
+// Testing generating a Release file with every hash and optional field.
+func TestGenerate(t *testing.T) {
+	r := NewRelease()
+	r.SetOrigin("afto beta repo")
+	r.SetLabel("apt.afto.repo")
+	r.SetCodename("afto")
+	r.SetVersion(2)
+	r.SetDescription("A default repo generated by afto")
+	r.SetDate(time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC))
+	r.SetValidUntil(time.Date(2026, 10, 24, 12, 0, 0, 0, time.UTC))
+	r.SetAcquireByHash(true)
+	r.AddIndexFile("Packages", []byte("Package: foo\n"))
+
+	want := `Origin: afto beta repo
+Label: apt.afto.repo
+Suite: beta
+Version: 2
+Codename: afto
+Date: Sat, 17 Oct 2026 12:00:00 UTC
+Valid-Until: Sat, 24 Oct 2026 12:00:00 UTC
+Architectures: iphoneos-arm
+Components: main
+Description: A default repo generated by afto
+Acquire-By-Hash: yes
+MD5Sum:
+ 82c88dbffc96d5a3d0e62207e8cdb288 13 Packages
+SHA1:
+ 2758b14c7cb6c3dc37494b41eec64f1cf49f440f 13 Packages
+SHA256:
+ 10ba9a762e3ef316246436a5f52aebf2b244fb7640aef5739b250edc51e1f9cb 13 Packages
+SHA512:
+ 2a66ad0769e3c0e0b094a584d84f4b215beb7fdb7ccb59897954e4f90ee75b85c30a853746762f6640f3b478e8fd4fa7d37f2288661d0aa3b9a532c9ddabc78e 13 Packages
+`
+	if got := r.Generate(); got != want {
+		t.Errorf("Generate() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", want, got)
+	}
+
+	// AddPackageSignature lists the Packages file as AddIndexFile does.
+	old := *r
+	old.files = nil
+	old.AddPackageSignature("Packages", []byte("Package: foo\n"))
+	if got := old.Generate(); got != want {
+		t.Errorf("AddPackageSignature(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", "Packages", want, got)
+	}
+}
+
+// Testing an existing Release file survives a parse and generate round trip.
+func TestParseString(t *testing.T) {
+	existing := `Origin: Example Repo
+Label: example
+Suite: stable
+Version: 12.5
+Codename: example
+Date: Sat, 17 Oct 2026 12:00:00 UTC
+Architectures: iphoneos-arm iphoneos-arm64
+Components: main
+Description: Tweaks by example.
+NotAutomatic: yes
+Changelogs: https://example.com/@CHANGEPATH@
+MD5Sum:
+ 82c88dbffc96d5a3d0e62207e8cdb288 13 Packages
+SHA256:
+ 10ba9a762e3ef316246436a5f52aebf2b244fb7640aef5739b250edc51e1f9cb 13 Packages
+`
+	r, err := NewRelease().ParseString(existing)
+	if err != nil {
+		t.Fatalf("ParseString() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", nil, err)
+	}
+	if r.Origin() != "Example Repo" || !r.NotAutomatic() || r.AcquireByHash() || r.Field("Changelogs") == "" {
+		t.Errorf("ParseString() failed test. fields were not parsed.")
+	}
+	if !r.Date().Equal(time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("ParseString() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", "Sat, 17 Oct 2026 12:00:00 UTC", r.Date())
+	}
+	if len(r.Files()) != 1 || r.Files()[0].Size != 13 || r.Files()[0].SHA1 != "" || r.Files()[0].SHA256 == "" {
+		t.Errorf("ParseString() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", "Packages with MD5Sum and SHA256", r.Files())
+	}
+
+	again, err := NewRelease().ParseString(r.Generate())
+	if err != nil || again.Generate() != r.Generate() {
+		t.Errorf("ParseString() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" (%v) \n\n", r.Generate(), again.Generate(), err)
+	}
+}
+
+// Testing malformed Release files are rejected.
+func TestParseStringErrors(t *testing.T) {
+	for _, existing := range []string{
+		"Origin: foo\nDate: yesterday\n",
+		"Origin: foo\nMD5Sum:\n 82c88dbffc96d5a3d0e62207e8cdb288 Packages\n",
+		"Origin: foo\nMD5Sum:\n 82c88dbffc96d5a3d0e62207e8cdb288 13 Packages\nSHA256:\n 10ba9a76 14 Packages\n",
+	} {
+		if _, err := NewRelease().ParseString(existing); err == nil {
+			t.Errorf("ParseString(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", existing, "error", err)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package repo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hako/afto/afutil"
+)
+
+// DebNotFoundError is returned when a deb file, or any deb file in a directory, cannot be found.
+type DebNotFoundError struct {
+	Path string
+}
+
+// Error returns the path which has no deb file.
+func (e *DebNotFoundError) Error() string {
+	switch {
+	case e.Path == "":
+		return "no .deb file given. unable to continue"
+	case filepath.Ext(e.Path) == ".deb":
+		return "\"" + e.Path + "\" not found. unable to continue"
+	}
+	return "no .deb file(s) found in \"" + e.Path + "\". unable to continue"
+}
+
+// InvalidRepoError is returned when a directory is not a repo. (it has no Packages or Release file)
+type InvalidRepoError struct {
+	Dir string
+	Err error
+}
+
+// Error returns why the directory is not a repo.
+func (e *InvalidRepoError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *InvalidRepoError) Unwrap() error {
+	return e.Err
+}
+
+// ParseError is returned when a deb or its version cannot be parsed.
+type ParseError struct {
+	Path string
+	Err  error
+}
+
+// Error returns the path of the deb and why it cannot be parsed.
+func (e *ParseError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// SignError is returned when the signing key cannot be loaded or the Release file cannot be signed.
+type SignError struct {
+	Key string
+	Err error
+}
+
+// Error returns why the repo cannot be signed.
+func (e *SignError) Error() string {
+	return "unable to sign: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *SignError) Unwrap() error {
+	return e.Err
+}
+
+// UnsignedError is returned when rebuilding a signed repo without a signing key, which would remove
+// its signatures, and publishing is not forced.
+type UnsignedError struct {
+	Dir string
+}
+
+// Error returns the signed repo.
+func (e *UnsignedError) Error() string {
+	return "\"" + e.Dir + "\" is signed, but no signing key is configured"
+}
+
+// IOError is returned when a file of a repo cannot be read or written.
+type IOError struct {
+	Op   string
+	Path string
+	Err  error
+}
+
+// Error returns the operation, the path and why it failed. (rename /srv/repo/Packages: permission denied)
+func (e *IOError) Error() string {
+	return e.Op + " " + e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *IOError) Unwrap() error {
+	return e.Err
+}
+
+// DependencyError is returned when the dependencies of the debs of a repo cannot be satisfied,
+// and publishing is not forced.
+type DependencyError struct {
+	Problems []afutil.DependencyProblem
+}
+
+// Error returns how many dependencies cannot be satisfied.
+func (e *DependencyError) Error() string {
+	return strconv.Itoa(len(e.Problems)) + " unsatisfiable dependencies found"
+}
+
+// UpToDateError is returned when updating a repo with a deb which is not newer than the one in the repo,
+// or with a deb whose package is not in the repo. (RepoVersion is empty then)
+type UpToDateError struct {
+	Package     string
+	Version     string
+	RepoVersion string
+}
+
+// Error returns why no update is available.
+func (e *UpToDateError) Error() string {
+	if e.RepoVersion == "" {
+		return "No update is available. (\"" + e.Package + "\" is not in the repo)"
+	}
+	return "No update is available. (\"" + e.Package + "\" " + e.RepoVersion + " in repo is not older than " + e.Version + ")"
+}
+
+// ioError turns the errors of the os package into an IOError, keeping the path which failed,
+// even when they are wrapped. (afutil.PublishError)
+func ioError(err error) error {
+	var pathErr *os.PathError
+	var linkErr *os.LinkError
+	switch {
+	case errors.As(err, &pathErr):
+		return &IOError{Op: pathErr.Op, Path: pathErr.Path, Err: pathErr.Err}
+	case errors.As(err, &linkErr):
+		return &IOError{Op: linkErr.Op, Path: linkErr.New, Err: linkErr.Err}
+	}
+	return err
+}
+
+// SuiteNotFoundError is returned when a suite is not one of the suites of the config of a repo.
+type SuiteNotFoundError struct {
+	Suite  string
+	Suites []string
+}
+
+// Error returns the suite and the suites of the repo.
+func (e *SuiteNotFoundError) Error() string {
+	return "suite \"" + e.Suite + "\" not found. (" + strings.Join(e.Suites, ", ") + ")"
+}
+
+// PackageNotFoundError is returned when no deb of a package, or of a version of it, is in a repo.
+type PackageNotFoundError struct {
+	Package string
+	Version string
+	Dir     string
+}
+
+// Error returns the package and version which are not in the repo.
+func (e *PackageNotFoundError) Error() string {
+	if e.Version == "" {
+		return "\"" + e.Package + "\" is not in \"" + e.Dir + "\""
+	}
+	return "\"" + e.Package + "\" " + e.Version + " is not in \"" + e.Dir + "\""
+}
+
+// DuplicateError is returned when adding a deb whose package, version and architecture are already
+// in a repo, or are given more than once.
+type DuplicateError struct {
+	Path         string
+	Package      string
+	Version      string
+	Architecture string
+}
+
+// Error returns the deb and the package, version and architecture it duplicates.
+func (e *DuplicateError) Error() string {
+	return e.Path + ": \"" + e.Package + "\" " + e.Version + " (" + e.Architecture + ") is already in the repo"
+}
+
+// OlderVersionError is returned when adding a deb older than the newest version of its package
+// and architecture in a repo, as the retention policy would prune it right away.
+type OlderVersionError struct {
+	Path         string
+	Package      string
+	Version      string
+	Architecture string
+	RepoVersion  string
+}
+
+// Error returns the deb and the newer version in the repo.
+func (e *OlderVersionError) Error() string {
+	return e.Path + ": \"" + e.Package + "\" " + e.Version + " (" + e.Architecture + ") is older than " + e.RepoVersion + " in the repo"
+}
@@ -0,0 +1,755 @@
+// Package repo includes the operations afto runs on Cydia repos, so they can be embedded in other tools.
+// Operations never exit the process, they return the typed errors of this package instead.
+package repo
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/hako/afto/afutil"
+	"github.com/hako/afto/deb"
+)
+
+// Repo represents a Cydia repo directory.
+// When its config lists suites, every suite has its own index files under dists/ and they share
+// the debs of pool/. The operations of a Repo work on its default suite, and Suite returns the others.
+type Repo struct {
+	dir       string
+	config    *afutil.Config
+	suite     string
+	force     bool
+	component string
+	assets    func(name string) ([]byte, error)
+	index     func(debs []string) []byte
+	logger    *log.Logger
+}
+
+// New creates a new Repo for the directory dir, configured by cfg.
+func New(dir string, cfg *afutil.Config) *Repo {
+	return &Repo{dir: dir, config: cfg}
+}
+
+// Dir returns the directory of the repo.
+func (r *Repo) Dir() string {
+	return r.dir
+}
+
+// Config returns the config of the repo.
+func (r *Repo) Config() *afutil.Config {
+	return r.config
+}
+
+// SetForce sets whether repos with unsatisfiable dependencies are published, whether signed repos
+// are published unsigned when there is no signing key, whether updates accept versions which are
+// not newer, and whether adds accept older versions.
+func (r *Repo) SetForce(force bool) {
+	r.force = force
+}
+
+// SetComponent sets the component new debs go to in the pool layout. (main, beta, testing)
+// By default they go to the first component of the config, and updated debs stay in their component.
+func (r *Repo) SetComponent(component string) {
+	r.component = component
+}
+
+// SetAssets sets the function returning the default icons of the repo.
+func (r *Repo) SetAssets(assets func(name string) ([]byte, error)) {
+	r.assets = assets
+}
+
+// SetIndex sets the function generating index.html from the deb file names of the repo.
+func (r *Repo) SetIndex(index func(debs []string) []byte) {
+	r.index = index
+}
+
+// SetLogger sets the logger the progress of operations is written to. Nothing is written by default.
+func (r *Repo) SetLogger(logger *log.Logger) {
+	r.logger = logger
+}
+
+// logln writes a line to the logger of the repo, if it has one.
+func (r *Repo) logln(msg string) {
+	if r.logger != nil {
+		r.logger.Println(msg)
+	}
+}
+
+// Create generates the repo from the debs in the directory src, moving them into the repo.
+// The debs are moved back when anything fails.
+func (r *Repo) Create(src string) (*afutil.BuildResult, error) {
+	r.logln("checking for deb files...")
+	debs, err := afutil.CheckDebWithPath(src)
+	if err != nil {
+		return nil, &DebNotFoundError{Path: src}
+	}
+	r.logln(strconv.Itoa(len(debs)) + " deb file(s) found.")
+
+	r.logln("generating repo: \"" + r.dir + "\"")
+	b := r.builder()
+	for _, d := range debs {
+		b.MoveDeb(filepath.Join(src, d))
+	}
+	return r.build(b)
+}
+
+// Add adds the debs at paths to the repo, copying them into it, and regenerates the repo once.
+// A path is a deb, a directory of debs or a glob pattern. (build/*.deb) Every deb is checked first, and a deb
+// whose package, version and architecture are already in the repo, or given twice, returns a DuplicateError.
+// Two debs of the same file name return an afutil.DuplicateDebError.
+// A deb older than the newest version of its package and architecture in the repo returns an OlderVersionError,
+// unless forced. The debs added are never pruned, and the older versions of their packages are kept as long as
+// the retention policy of the config allows.
+func (r *Repo) Add(paths ...string) (*afutil.BuildResult, error) {
+	r.logln("checking for deb files...")
+	inputs, err := expandDebs(paths)
+	if err != nil {
+		return nil, err
+	}
+	r.logln(strconv.Itoa(len(inputs)) + " deb file(s) found.")
+	if _, err := afutil.GetRepo(r.dir); err != nil {
+		return nil, &InvalidRepoError{Dir: r.dir, Err: err}
+	}
+	b := r.builder()
+	entries, err := debEntries(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// Debs are identified by their package, version and architecture.
+	seen := map[string]bool{}
+	key := func(c deb.DpkgInterface) string {
+		return c.Package() + " " + c.Version() + " " + c.Arch()
+	}
+	newest := map[string]string{}
+	for _, e := range entries {
+		seen[key(e.Entry)] = true
+		pkgArch := e.Entry.Package() + " " + e.Entry.Arch()
+		if cmp, _ := deb.CompareVersions(e.Entry.Version(), newest[pkgArch]); newest[pkgArch] == "" || cmp > 0 {
+			newest[pkgArch] = e.Entry.Version()
+		}
+	}
+	var added []string
+	keep := map[string]bool{}
+	for _, path := range inputs {
+		c, err := afutil.ParseDeb(path)
+		if err != nil {
+			return nil, &ParseError{Path: path, Err: err}
+		}
+		if c.Package() == "" || c.Arch() == "" {
+			return nil, &ParseError{Path: path, Err: errors.New("control file has no Package or Architecture field")}
+		}
+		if _, err := deb.ParseVersion(c.Version()); err != nil {
+			return nil, &ParseError{Path: path, Err: err}
+		}
+		if seen[key(c)] {
+			return nil, &DuplicateError{Path: path, Package: c.Package(), Version: c.Version(), Architecture: c.Arch()}
+		}
+		// An older version would be pruned right away, unless forced.
+		repoVersion := newest[c.Package()+" "+c.Arch()]
+		if cmp, _ := deb.CompareVersions(c.Version(), repoVersion); repoVersion != "" && cmp < 0 && !r.force {
+			return nil, &OlderVersionError{Path: path, Package: c.Package(), Version: c.Version(), Architecture: c.Arch(), RepoVersion: repoVersion}
+		}
+		seen[key(c)] = true
+		r.logln("adding \"" + c.Package() + "\" version " + c.Version())
+		b.AddDeb(path)
+		added = append(added, c.Package())
+		keep[path] = true
+	}
+	// The debs just added are never pruned, only the older versions they leave behind.
+	for _, pkg := range added {
+		if err := r.prune(b, pkg, keep); err != nil {
+			return nil, err
+		}
+	}
+	return r.build(b)
+}
+
+// Remove removes the debs of pkg from the repo and regenerates it. The debs are found through the
+// Packages file of the repo, and only the ones of version and arch are removed, unless they are empty.
+// A DependencyError is returned when packages left in the repo depend on the removed ones, unless forced.
+func (r *Repo) Remove(pkg string, version string, arch string) (*afutil.BuildResult, error) {
+	entries, err := r.packages()
+	if err != nil {
+		return nil, err
+	}
+	removed := map[string]bool{}
+	for _, e := range entries {
+		if e.Package() != pkg || arch != "" && e.Arch() != arch {
+			continue
+		}
+		if version != "" {
+			if cmp, err := deb.CompareVersions(e.Version(), version); err != nil || cmp != 0 {
+				continue
+			}
+		}
+		removed[strings.TrimPrefix(e.Filename(), "./")] = true
+	}
+	if len(removed) == 0 {
+		return nil, &PackageNotFoundError{Package: pkg, Version: version, Dir: r.dir}
+	}
+
+	b := r.builder()
+	debs, err := debEntries(b)
+	if err != nil {
+		return nil, err
+	}
+	var left, gone []*deb.Packages
+	for _, d := range debs {
+		if !removed[d.Name] {
+			left = append(left, d.Entry)
+			continue
+		}
+		r.logln("removing \"" + d.Name + "\"")
+		gone = append(gone, d.Entry)
+		b.RemoveDeb(d.Name)
+	}
+
+	// Packages which depend on the removed ones would no longer install.
+	problems, err := afutil.CheckRemoval(left, gone)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range problems {
+		r.logln("unsatisfiable dependency: " + p.String())
+	}
+	if len(problems) > 0 && !r.force {
+		return nil, &DependencyError{Problems: problems}
+	}
+	return r.build(b)
+}
+
+// Update adds the deb at path to the repo as the newest version of its package, copying it into the repo.
+// The older versions of the package are kept as long as the retention policy of the config allows.
+// Only a version newer than the ones of the same architecture is accepted, unless forced, which replaces
+// the versions which are not older. The debs of other architectures are left alone.
+// An UpToDateError is returned otherwise.
+func (r *Repo) Update(path string) (*afutil.BuildResult, error) {
+	r.logln("checking for deb files...")
+	input, err := afutil.CheckDebWithFile(path)
+	if err != nil {
+		return nil, &DebNotFoundError{Path: path}
+	}
+	if _, err := os.Stat(input); os.IsNotExist(err) {
+		return nil, &DebNotFoundError{Path: path}
+	}
+	r.logln("deb file: \"" + filepath.Base(input) + "\" found.")
+	if _, err := afutil.GetRepo(r.dir); err != nil {
+		return nil, &InvalidRepoError{Dir: r.dir, Err: err}
+	}
+	b := r.builder()
+	debs, err := debEntries(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(debs) == 0 {
+		return nil, &DebNotFoundError{Path: r.dir}
+	}
+	r.logln(strconv.Itoa(len(debs)) + " deb file(s) found.")
+	r.logln("updating repo: \"" + r.dir + "\"")
+	inputDeb, err := afutil.ParseDeb(input)
+	if err != nil {
+		return nil, &ParseError{Path: input, Err: err}
+	}
+	inputVersion, err := deb.ParseVersion(inputDeb.Version())
+	if err != nil {
+		return nil, &ParseError{Path: input, Err: err}
+	}
+
+	found := false
+	for _, d := range debs {
+		path := d.Path
+		if abs, _ := filepath.Abs(path); abs == input {
+			continue
+		}
+		repoDeb := d.Entry
+		if repoDeb.Package() != inputDeb.Package() {
+			continue
+		}
+		// The updated deb stays in the component of its package.
+		if !found && r.component == "" {
+			b.SetComponent(debComponent(d.Name))
+		}
+		found = true
+		// Versions are only compared with the debs of the same architecture.
+		if repoDeb.Arch() != inputDeb.Arch() {
+			continue
+		}
+		repoVersion, err := deb.ParseVersion(repoDeb.Version())
+		if err != nil {
+			return nil, &ParseError{Path: path, Err: err}
+		}
+		// Only strictly newer versions are updates, unless forced.
+		if inputVersion.Compare(repoVersion) <= 0 {
+			if !r.force {
+				return nil, &UpToDateError{Package: inputDeb.Package(), Version: inputVersion.String(), RepoVersion: repoVersion.String()}
+			}
+			b.RemoveDeb(d.Name)
+		}
+	}
+	if !found {
+		return nil, &UpToDateError{Package: inputDeb.Package(), Version: inputVersion.String()}
+	}
+	r.logln("Update is available for \"" + inputDeb.Package() + "\" version " + inputDeb.Version())
+
+	b.AddDeb(input)
+	if err := r.prune(b, inputDeb.Package(), nil); err != nil {
+		return nil, err
+	}
+	return r.build(b)
+}
+
+// Suites returns the suites of the repo. A repo whose config lists no suites has the suite of its config.
+func (r *Repo) Suites() []string {
+	if len(r.config.Suites) == 0 {
+		return []string{r.config.Suite}
+	}
+	return r.config.Suites
+}
+
+// Suite returns the repo of suite, which shares the directory and the pool of r when the config lists suites.
+// An empty suite is the default suite of the config. (the first one, unless suite is set)
+func (r *Repo) Suite(suite string) (*Repo, error) {
+	if suite == "" {
+		suite = r.config.Suite
+	}
+	if len(r.config.Suites) == 0 {
+		if suite != r.config.Suite {
+			return nil, &SuiteNotFoundError{Suite: suite, Suites: r.Suites()}
+		}
+		return r, nil
+	}
+	if !r.config.HasSuite(suite) {
+		return nil, &SuiteNotFoundError{Suite: suite, Suites: r.Suites()}
+	}
+	s := *r
+	s.suite = suite
+	return &s, nil
+}
+
+// suiteName returns the suite of the repo.
+func (r *Repo) suiteName() string {
+	if r.suite != "" {
+		return r.suite
+	}
+	return r.config.Suite
+}
+
+// PromoteResult represents the builds of the two suites of a promotion.
+type PromoteResult struct {
+	From *afutil.BuildResult
+	To   *afutil.BuildResult
+}
+
+// Promote moves the debs of version of pkg, for every architecture, from the suite from to the suite to,
+// and regenerates both suites. The debs stay in the pool, and only the index files of the suites change.
+// The older versions of pkg in to are kept as long as the retention policy of the config allows.
+// A newer version in to for the same architecture is not replaced, unless forced.
+// Both suites are checked before either is published, and to is published first, so the debs are never missing.
+func (r *Repo) Promote(pkg string, version string, from string, to string) (*PromoteResult, error) {
+	src, err := r.Suite(from)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := r.Suite(to)
+	if err != nil {
+		return nil, err
+	}
+	if src.suiteName() == dst.suiteName() {
+		return nil, errors.New("unable to promote \"" + pkg + "\" from \"" + from + "\" to the same suite")
+	}
+	if _, err := afutil.GetRepo(src.dir); err != nil {
+		return nil, &InvalidRepoError{Dir: src.dir, Err: err}
+	}
+	r.logln("promoting \"" + pkg + "\" " + version + " from \"" + src.suiteName() + "\" to \"" + dst.suiteName() + "\"")
+
+	fb := src.builder()
+	promoted, err := findDebs(fb, pkg, version)
+	if err != nil {
+		return nil, err
+	}
+	if len(promoted) == 0 {
+		return nil, &PackageNotFoundError{Package: pkg, Version: version, Dir: src.dir}
+	}
+
+	// The promoted debs replace the versions of their package and architecture which are not older,
+	// and stay in their component.
+	tb := dst.builder()
+	debs, err := debEntries(tb)
+	if err != nil {
+		return nil, err
+	}
+	archs := map[string]bool{}
+	for _, d := range promoted {
+		archs[d.Entry.Arch()] = true
+	}
+	for _, d := range debs {
+		c := d.Entry
+		if c.Package() != pkg || !archs[c.Arch()] {
+			continue
+		}
+		cmp, _ := deb.CompareVersions(c.Version(), version)
+		if cmp > 0 && !r.force {
+			return nil, &UpToDateError{Package: pkg, Version: version, RepoVersion: c.Version()}
+		}
+		if cmp >= 0 {
+			tb.RemoveDeb(d.Name)
+		}
+	}
+	for _, d := range promoted {
+		r.logln("promoting \"" + d.Name + "\"")
+		fb.RemoveDeb(d.Name)
+		if r.component == "" {
+			tb.SetComponent(debComponent(d.Name))
+		}
+		tb.AddDeb(d.Path)
+	}
+	if err := dst.prune(tb, pkg, nil); err != nil {
+		return nil, err
+	}
+
+	if err := dst.check(tb); err != nil {
+		return nil, err
+	}
+	if err := src.check(fb); err != nil {
+		return nil, err
+	}
+	result := &PromoteResult{}
+	if result.To, err = dst.publish(tb); err != nil {
+		return nil, err
+	}
+	if result.From, err = src.publish(fb); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Prune removes the debs which expired under the retention policy of the config from the repo,
+// and regenerates it. (keep_versions, keep_for)
+func (r *Repo) Prune() (*afutil.BuildResult, error) {
+	if _, err := afutil.GetRepo(r.dir); err != nil {
+		return nil, &InvalidRepoError{Dir: r.dir, Err: err}
+	}
+	r.logln("pruning repo: \"" + r.dir + "\"")
+	b := r.builder()
+	if err := r.prune(b, "", nil); err != nil {
+		return nil, err
+	}
+	return r.build(b)
+}
+
+// List returns the entries of the Packages file of the repo which filter selects, sorted by package and version.
+func (r *Repo) List(filter afutil.PackageFilter) ([]*deb.Packages, error) {
+	entries, err := r.packages()
+	if err != nil {
+		return nil, err
+	}
+	var selected []*deb.Packages
+	for _, e := range entries {
+		if filter.Match(e) {
+			selected = append(selected, e)
+		}
+	}
+	return selected, nil
+}
+
+// Show returns the entries of pkg in the Packages file of the repo, sorted by version.
+// Only the entries of version are returned, unless it is empty.
+func (r *Repo) Show(pkg string, version string) ([]*deb.Packages, error) {
+	entries, err := r.packages()
+	if err != nil {
+		return nil, err
+	}
+	var selected []*deb.Packages
+	for _, e := range entries {
+		if e.Package() != pkg {
+			continue
+		}
+		if version != "" {
+			if cmp, err := deb.CompareVersions(e.Version(), version); err != nil || cmp != 0 {
+				continue
+			}
+		}
+		selected = append(selected, e)
+	}
+	if len(selected) == 0 {
+		return nil, &PackageNotFoundError{Package: pkg, Version: version, Dir: r.dir}
+	}
+	return selected, nil
+}
+
+// Regenerate regenerates the index files of the repo in place, for the debs which are in it now.
+func (r *Repo) Regenerate() (*afutil.BuildResult, error) {
+	r.logln("regenerating repo: \"" + r.dir + "\"")
+	return r.build(r.builder())
+}
+
+// Sign signs the Release files of the repo, the root one and the ones of dists/, with the secret key in keyFile
+// into Release.gpg and InRelease.
+func (r *Repo) Sign(keyFile string, passphrase []byte) error {
+	if _, err := afutil.GetRepo(r.dir); err != nil {
+		return &InvalidRepoError{Dir: r.dir, Err: err}
+	}
+	r.logln("signing repo \"" + r.dir + "\"")
+	if err := afutil.SignRepo(r.dir, keyFile, passphrase); err != nil {
+		return &SignError{Key: keyFile, Err: err}
+	}
+	return nil
+}
+
+// Verify audits the repo, checking the signatures against keyring when it is not nil.
+func (r *Repo) Verify(keyring openpgp.KeyRing) (*afutil.VerifyReport, error) {
+	report, err := afutil.VerifyRepo(r.dir, keyring)
+	if err != nil {
+		return nil, ioError(err)
+	}
+	return report, nil
+}
+
+// builder creates the builder of the repo.
+func (r *Repo) builder() *afutil.Builder {
+	b := afutil.NewBuilder(r.dir, r.config)
+	b.SetAssets(r.assets)
+	b.SetIndex(r.index)
+	b.SetComponent(r.component)
+	b.SetSuite(r.suite)
+	return b
+}
+
+// build checks the dependencies of the debs the repo will hold, loads the signing key
+// and then builds the repo.
+func (r *Repo) build(b *afutil.Builder) (*afutil.BuildResult, error) {
+	if err := r.check(b); err != nil {
+		return nil, err
+	}
+	return r.publish(b)
+}
+
+// check checks the dependencies of the debs the repo will hold and loads the signing key into b,
+// so a broken key is caught before generating. A signed repo without a signing key returns
+// an UnsignedError, unless forced, as publishing it would remove its signatures.
+func (r *Repo) check(b *afutil.Builder) error {
+	debs, err := debEntries(b)
+	if err != nil {
+		return err
+	}
+	if err := r.checkDependencies(debs); err != nil {
+		return err
+	}
+	key, err := r.signingKey()
+	if err != nil {
+		return err
+	}
+	if key == nil && !r.force {
+		signed, err := afutil.IsSigned(r.dir)
+		if err != nil {
+			return ioError(err)
+		}
+		if signed {
+			return &UnsignedError{Dir: r.dir}
+		}
+	}
+	b.SetKey(key)
+	return nil
+}
+
+// publish builds the repo and logs what was generated.
+func (r *Repo) publish(b *afutil.Builder) (*afutil.BuildResult, error) {
+	result, err := b.Build()
+	if err != nil {
+		return nil, ioError(err)
+	}
+	r.logln(strconv.Itoa(result.Read) + " deb file(s) read, " + strconv.Itoa(result.Cached) + " from cache.")
+	r.logln("generated Packages file.")
+	if r.config.Layout == afutil.LayoutPool {
+		r.logln("generated dists/" + r.suiteName() + " indexes. (components: " + strings.Join(result.Components, ", ") +
+			"; architectures: " + strings.Join(result.Architectures, ", ") + ")")
+	}
+	r.logln("compressed Packages file. (" + strings.Join(result.Compressions, ", ") + ")")
+	r.logln("created Release file.")
+	if result.Signed {
+		r.logln("signed Release file. (Release.gpg, InRelease)")
+	}
+	if result.Unsigned {
+		r.logln("removed the signatures of the Release file, the repo is no longer signed!")
+	}
+	if result.ByHash {
+		r.logln("created by-hash files.")
+	}
+	r.logln("published repo: \"" + r.dir + "\"")
+	return result, nil
+}
+
+// checkDependencies checks that the dependencies between the debs can be satisfied.
+// A repo with unsatisfiable dependencies is not published, unless forced.
+func (r *Repo) checkDependencies(debs []afutil.DebEntry) error {
+	var entries []*deb.Packages
+	for _, d := range debs {
+		entries = append(entries, d.Entry)
+	}
+	problems, err := afutil.CheckDependencies(entries)
+	if err != nil {
+		return err
+	}
+	for _, p := range problems {
+		r.logln("unsatisfiable dependency: " + p.String())
+	}
+	if len(problems) > 0 && !r.force {
+		return &DependencyError{Problems: problems}
+	}
+	return nil
+}
+
+// signingKey loads the signing key of the repo config, or returns nil when the repo is not signed.
+func (r *Repo) signingKey() (*openpgp.Entity, error) {
+	if r.config.SigningKey == "" {
+		return nil, nil
+	}
+	passphrase, err := afutil.ReadPassphrase(r.config.PassphraseFile)
+	if err != nil {
+		return nil, &SignError{Key: r.config.SigningKey, Err: err}
+	}
+	key, err := afutil.LoadSigningKey(r.config.SigningKey, passphrase)
+	if err != nil {
+		return nil, &SignError{Key: r.config.SigningKey, Err: err}
+	}
+	return key, nil
+}
+
+// packages returns the entries of the Packages files of the suite of the repo, sorted by package and version.
+func (r *Repo) packages() ([]*deb.Packages, error) {
+	if _, err := afutil.GetRepo(r.dir); err != nil {
+		return nil, &InvalidRepoError{Dir: r.dir, Err: err}
+	}
+	load := afutil.LoadPackages
+	if len(r.config.Suites) > 0 {
+		load = func(dir string) ([]*deb.Packages, error) {
+			return afutil.LoadSuitePackages(dir, r.suiteName())
+		}
+	}
+	entries, err := load(r.dir)
+	if err != nil {
+		return nil, &ParseError{Path: r.dir, Err: err}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Package() != entries[j].Package() {
+			return entries[i].Package() < entries[j].Package()
+		}
+		c, _ := deb.CompareVersions(entries[i].Version(), entries[j].Version())
+		return c < 0
+	})
+	return entries, nil
+}
+
+// prune removes the debs which expired under the retention policy of the repo from b.
+// Only the debs of pkg are pruned, unless pkg is empty, and the debs at the paths in keep never are.
+func (r *Repo) prune(b *afutil.Builder, pkg string, keep map[string]bool) error {
+	debs, err := debEntries(b)
+	if err != nil {
+		return err
+	}
+	expired, err := afutil.ExpiredDebs(debs, r.config, pkg, time.Now())
+	if err != nil {
+		if e := ioError(err); e != err {
+			return e
+		}
+		return &ParseError{Path: r.dir, Err: err}
+	}
+	for _, d := range expired {
+		if keep[d.Path] {
+			continue
+		}
+		r.logln("pruned \"" + d.Name + "\"")
+		b.RemoveDeb(d.Name)
+	}
+	return nil
+}
+
+// expandDebs returns the absolute paths of the debs at paths, which are debs, directories of debs
+// or glob patterns.
+func expandDebs(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, &DebNotFoundError{}
+	}
+	var debs []string
+	for _, p := range paths {
+		matches := []string{p}
+		if strings.ContainsAny(p, "*?[") {
+			matches, _ = filepath.Glob(p)
+			if len(matches) == 0 {
+				return nil, &DebNotFoundError{Path: p}
+			}
+		}
+		for _, m := range matches {
+			info, err := os.Stat(m)
+			if err != nil {
+				return nil, &DebNotFoundError{Path: m}
+			}
+			if info.IsDir() {
+				names, err := afutil.CheckDebWithPath(m)
+				if err != nil {
+					return nil, &DebNotFoundError{Path: m}
+				}
+				for _, name := range names {
+					abs, _ := filepath.Abs(filepath.Join(m, name))
+					debs = append(debs, abs)
+				}
+				continue
+			}
+			abs, err := afutil.CheckDebWithFile(m)
+			if err != nil {
+				return nil, &DebNotFoundError{Path: m}
+			}
+			debs = append(debs, abs)
+		}
+	}
+	return debs, nil
+}
+
+// findDebs returns the debs of version of pkg which b holds, for every architecture.
+func findDebs(b *afutil.Builder, pkg string, version string) ([]afutil.DebEntry, error) {
+	debs, err := debEntries(b)
+	if err != nil {
+		return nil, err
+	}
+	var found []afutil.DebEntry
+	for _, d := range debs {
+		c := d.Entry
+		if cmp, err := deb.CompareVersions(c.Version(), version); c.Package() == pkg && err == nil && cmp == 0 {
+			found = append(found, d)
+		}
+	}
+	return found, nil
+}
+
+// debEntries returns the debs b holds with their Packages entries, which come from the cache of the repo.
+func debEntries(b *afutil.Builder) ([]afutil.DebEntry, error) {
+	entries, err := b.Entries()
+	if err == nil {
+		return entries, nil
+	}
+	if e := ioError(err); e != err {
+		return nil, e
+	}
+	var debErr *afutil.DebError
+	if errors.As(err, &debErr) {
+		return nil, &ParseError{Path: debErr.Name, Err: debErr.Err}
+	}
+	return nil, err
+}
+
+// debComponent returns the component of the pool the deb name is in, or an empty string when it is not in the pool.
+// (main for pool/main/c/com.example.tweakexample/...)
+func debComponent(name string) string {
+	parts := strings.Split(name, "/")
+	if len(parts) < 3 || parts[0] != "pool" {
+		return ""
+	}
+	return parts[1]
+}
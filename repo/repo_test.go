@@ -0,0 +1,489 @@
+package repo
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hako/afto/afutil"
+)
+
+// testDeb is the absolute path to the test deb.
+var testDeb, _ = filepath.Abs("../test_data/deb/com.yourcompany.tweakexample_0.0.1-2_iphoneos-arm.deb")
+
+// tempRepo creates a directory holding a copy of the test deb.
+func tempRepo(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "afto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := afutil.Copy(testDeb, filepath.Join(dir, filepath.Base(testDeb))); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// writeDeb writes a deb of version of pkg for arch, holding only its control file, to dir and returns its path.
+func writeDeb(t *testing.T, dir string, pkg string, version string, arch string) string {
+	control := "Package: " + pkg + "\nVersion: " + version + "\nArchitecture: " + arch + "\n"
+	var tarball bytes.Buffer
+	tw := tar.NewWriter(&tarball)
+	tw.WriteHeader(&tar.Header{Name: "./control", Mode: 0644, Size: int64(len(control))})
+	tw.Write([]byte(control))
+	tw.Close()
+	var empty bytes.Buffer
+	tar.NewWriter(&empty).Close()
+
+	ar := bytes.NewBufferString("!<arch>\n")
+	for _, m := range []struct {
+		name string
+		data []byte
+	}{{"debian-binary", []byte("2.0\n")}, {"control.tar", tarball.Bytes()}, {"data.tar", empty.Bytes()}} {
+		size := strconv.Itoa(len(m.data))
+		ar.WriteString(m.name + strings.Repeat(" ", 16-len(m.name)) + "0           0     0     100644  " + size + strings.Repeat(" ", 10-len(size)) + "`\n")
+		ar.Write(m.data)
+		if len(m.data)%2 == 1 {
+			ar.WriteString("\n")
+		}
+	}
+	path := filepath.Join(dir, pkg+"_"+version+"_"+arch+".deb")
+	if err := ioutil.WriteFile(path, ar.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Testing a repo is created from a directory of debs and regenerated in place.
+func TestCreate(t *testing.T) {
+	src := tempRepo(t)
+	defer os.RemoveAll(src)
+	dir := filepath.Join(src, "repo")
+
+	r := New(dir, afutil.DefaultConfig())
+	result, err := r.Create(src)
+	if err != nil {
+		t.Fatalf("Create(%q) failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", src, nil, err)
+	}
+	if len(result.Debs) != 1 || len(result.Changes) != 1 || result.Changes[0].Kind != afutil.ChangeAdded {
+		t.Errorf("Create(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", src, "1 added deb", result.Changes)
+	}
+	if result.Read != 1 || result.Cached != 0 {
+		t.Errorf("Create(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%d read, %d cached\" \n\n", src, "1 read, 0 cached", result.Read, result.Cached)
+	}
+	if _, err := os.Stat(filepath.Join(dir, filepath.Base(testDeb))); err != nil {
+		t.Errorf("Create(%q) failed test. the deb was not moved into the repo. (%v)", src, err)
+	}
+
+	result, err = r.Regenerate()
+	if err != nil || len(result.Changes) != 0 || result.Read != 0 || result.Cached != 1 {
+		t.Errorf("Regenerate() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" (%v) \n\n", "no changes, 1 cached deb", result, err)
+	}
+}
+
+// Testing debs, directories and globs are added to a repo, rejecting duplicates.
+func TestAdd(t *testing.T) {
+	src := tempRepo(t)
+	defer os.RemoveAll(src)
+	dir := filepath.Join(src, "repo")
+	if _, err := afutil.NewBuilder(dir, afutil.DefaultConfig()).Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := New(dir, afutil.DefaultConfig())
+	glob := filepath.Join(src, "*.deb")
+	result, err := r.Add(glob)
+	if err != nil {
+		t.Fatalf("Add(%q) failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", glob, nil, err)
+	}
+	if len(result.Changes) != 1 || result.Changes[0].Kind != afutil.ChangeAdded {
+		t.Errorf("Add(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", glob, "1 added deb", result.Changes)
+	}
+	for _, path := range []string{filepath.Join(dir, filepath.Base(testDeb)), filepath.Join(src, filepath.Base(testDeb))} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Add(%q) failed test. the deb was not copied into the repo. (%v)", glob, err)
+		}
+	}
+
+	var paramTests = []struct {
+		params []string
+		want   string
+	}{
+		{[]string{testDeb}, "*repo.DuplicateError"},
+		{[]string{src}, "*repo.DuplicateError"},
+		{[]string{filepath.Join(dir, "Packages")}, "*repo.DebNotFoundError"},
+		{[]string{filepath.Join(src, "missing*.deb")}, "*repo.DebNotFoundError"},
+		{nil, "*repo.DebNotFoundError"},
+	}
+	for _, p := range paramTests {
+		_, err := r.Add(p.params...)
+		got := "<nil>"
+		switch err.(type) {
+		case *DuplicateError:
+			got = "*repo.DuplicateError"
+		case *DebNotFoundError:
+			got = "*repo.DebNotFoundError"
+		case nil:
+		default:
+			got = err.Error()
+		}
+		if got != p.want {
+			t.Errorf("Add(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", p.params, p.want, got)
+		}
+	}
+	_, err = New(src, afutil.DefaultConfig()).Add(testDeb)
+	if _, ok := err.(*InvalidRepoError); !ok {
+		t.Errorf("Add(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", testDeb, "*repo.InvalidRepoError", err)
+	}
+
+	// Older versions are refused, newer versions and other architectures are added and never pruned.
+	debs, err := ioutil.TempDir("", "afto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(debs)
+	older := writeDeb(t, debs, "com.yourcompany.tweakexample", "0.0.1-1", "iphoneos-arm")
+	if _, err := r.Add(older); err == nil {
+		t.Errorf("Add(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", older, "*repo.OlderVersionError", err)
+	} else if _, ok := err.(*OlderVersionError); !ok {
+		t.Errorf("Add(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", older, "*repo.OlderVersionError", err)
+	}
+	inputs := []string{
+		writeDeb(t, debs, "com.yourcompany.tweakexample", "0.0.1-1", "iphoneos-arm64"),
+		writeDeb(t, debs, "com.yourcompany.tweakexample", "0.0.2", "iphoneos-arm"),
+		writeDeb(t, debs, "com.yourcompany.tweakexample", "0.0.3", "iphoneos-arm"),
+	}
+	result, err = r.Add(inputs...)
+	if err != nil || len(result.Debs) != 3 {
+		t.Fatalf("Add(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" (%v) \n\n", inputs, "3 debs", result, err)
+	}
+	for _, path := range inputs {
+		if _, err := os.Stat(filepath.Join(dir, filepath.Base(path))); err != nil {
+			t.Errorf("Add(%q) failed test. the deb was pruned. (%v)", path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, filepath.Base(testDeb))); !os.IsNotExist(err) {
+		t.Errorf("Add(%q) failed test. the older version was not pruned. (%v)", inputs, err)
+	}
+
+	// Two debs of the same file name are refused, rather than one replacing the other.
+	var same []string
+	for i, version := range []string{"0.0.4", "0.0.5"} {
+		sub := filepath.Join(debs, strconv.Itoa(i))
+		os.Mkdir(sub, 0755)
+		path := filepath.Join(sub, "tweak.deb")
+		os.Rename(writeDeb(t, sub, "com.yourcompany.tweakexample", version, "iphoneos-arm"), path)
+		same = append(same, path)
+	}
+	if _, err := r.Add(same...); err == nil {
+		t.Errorf("Add(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", same, "*afutil.DuplicateDebError", err)
+	} else if _, ok := err.(*afutil.DuplicateDebError); !ok {
+		t.Errorf("Add(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", same, "*afutil.DuplicateDebError", err)
+	}
+}
+
+// Testing an update only replaces the versions of its architecture.
+func TestUpdate(t *testing.T) {
+	src := tempRepo(t)
+	defer os.RemoveAll(src)
+	dir := filepath.Join(src, "repo")
+	writeDeb(t, src, "com.yourcompany.tweakexample", "0.0.2", "iphoneos-arm64")
+	r := New(dir, afutil.DefaultConfig())
+	if _, err := r.Create(src); err != nil {
+		t.Fatal(err)
+	}
+
+	debs, err := ioutil.TempDir("", "afto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(debs)
+	var paramTests = []struct {
+		version, arch string
+		want          []string
+	}{
+		{"0.0.2", "iphoneos-arm", []string{"com.yourcompany.tweakexample_0.0.2_iphoneos-arm.deb", "com.yourcompany.tweakexample_0.0.2_iphoneos-arm64.deb"}},
+		{"0.0.3", "iphoneos-arm64", []string{"com.yourcompany.tweakexample_0.0.2_iphoneos-arm.deb", "com.yourcompany.tweakexample_0.0.3_iphoneos-arm64.deb"}},
+	}
+	for _, p := range paramTests {
+		path := writeDeb(t, debs, "com.yourcompany.tweakexample", p.version, p.arch)
+		result, err := r.Update(path)
+		if err != nil {
+			t.Fatalf("Update(%q) failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", path, nil, err)
+		}
+		var names []string
+		for _, d := range result.Debs {
+			names = append(names, d.Name)
+		}
+		if strings.Join(names, " ") != strings.Join(p.want, " ") {
+			t.Errorf("Update(%q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", path, p.want, names)
+		}
+	}
+}
+
+// Testing the packages of a repo are listed and shown.
+func TestListShow(t *testing.T) {
+	src := tempRepo(t)
+	defer os.RemoveAll(src)
+	dir := filepath.Join(src, "repo")
+	r := New(dir, afutil.DefaultConfig())
+	if _, err := r.Create(src); err != nil {
+		t.Fatal(err)
+	}
+
+	var paramTests = []struct {
+		filter afutil.PackageFilter
+		want   int
+	}{
+		{afutil.PackageFilter{}, 1},
+		{afutil.PackageFilter{Pattern: "tweakexample"}, 1},
+		{afutil.PackageFilter{Architecture: "iphoneos-arm64"}, 0},
+	}
+	for _, p := range paramTests {
+		entries, err := r.List(p.filter)
+		if err != nil || len(entries) != p.want {
+			t.Errorf("List(%v) failed test. \n\n\rWant: \n\r\"%d\" \n\rGot: \n\r\"%d\" (%v) \n\n", p.filter, p.want, len(entries), err)
+		}
+	}
+
+	entries, err := r.Show("com.yourcompany.tweakexample", "0.0.1-2")
+	if err != nil || len(entries) != 1 || entries[0].Filename() != "./"+filepath.Base(testDeb) {
+		t.Errorf("Show() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" (%v) \n\n", filepath.Base(testDeb), entries, err)
+	}
+	_, err = r.Show("com.yourcompany.tweakexample", "0.0.2")
+	if _, ok := err.(*PackageNotFoundError); !ok {
+		t.Errorf("Show() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", "*repo.PackageNotFoundError", err)
+	}
+}
+
+// Testing debs are removed from a repo by package, version and architecture.
+func TestRemove(t *testing.T) {
+	src := tempRepo(t)
+	defer os.RemoveAll(src)
+	dir := filepath.Join(src, "repo")
+	r := New(dir, afutil.DefaultConfig())
+	if _, err := r.Create(src); err != nil {
+		t.Fatal(err)
+	}
+
+	var paramTests = []struct {
+		pkg, version, arch string
+	}{
+		{"com.yourcompany.tweak", "", ""},
+		{"com.yourcompany.tweakexample", "0.0.2", ""},
+		{"com.yourcompany.tweakexample", "", "iphoneos-arm64"},
+	}
+	for _, p := range paramTests {
+		_, err := r.Remove(p.pkg, p.version, p.arch)
+		if _, ok := err.(*PackageNotFoundError); !ok {
+			t.Errorf("Remove(%q, %q, %q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", p.pkg, p.version, p.arch, "*repo.PackageNotFoundError", err)
+		}
+	}
+
+	result, err := r.Remove("com.yourcompany.tweakexample", "0.0.1-2", "iphoneos-arm")
+	if err != nil {
+		t.Fatalf("Remove() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", nil, err)
+	}
+	if len(result.Debs) != 0 || len(result.Changes) != 1 || result.Changes[0].Kind != afutil.ChangeRemoved {
+		t.Errorf("Remove() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" \n\n", "1 removed deb", result.Changes)
+	}
+	if _, err := os.Stat(filepath.Join(dir, filepath.Base(testDeb))); !os.IsNotExist(err) {
+		t.Errorf("Remove() failed test. the deb was not removed from the repo. (%v)", err)
+	}
+}
+
+// Testing a package is promoted from a suite to another for every architecture, the suites sharing the pool.
+func TestPromote(t *testing.T) {
+	src := tempRepo(t)
+	defer os.RemoveAll(src)
+	dir := filepath.Join(src, "repo")
+	writeDeb(t, src, "com.yourcompany.tweakexample", "0.0.1-2", "iphoneos-arm64")
+	writeDeb(t, src, "com.example.other", "1.0", "iphoneos-arm")
+	cfg, err := afutil.ParseConfig("afto.yaml", []byte("suites: [beta, stable]\nlayout: pool\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := New(dir, cfg)
+	beta, err := r.Suite("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := beta.Create(src); err != nil {
+		t.Fatalf("Create(%q) failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", src, nil, err)
+	}
+	stable, err := r.Suite("stable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	debs, err := ioutil.TempDir("", "afto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(debs)
+	if _, err := stable.Add(writeDeb(t, debs, "com.example.other", "2.0", "iphoneos-arm")); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := r.Promote("com.yourcompany.tweakexample", "0.0.1-2", "beta", "stable")
+	if err != nil {
+		t.Fatalf("Promote() failed test. \n\n\rWant: \n\r\"%v\" \n\rGot: \n\r\"%v\" \n\n", nil, err)
+	}
+	if len(result.From.Debs) != 1 || len(result.To.Debs) != 3 {
+		t.Errorf("Promote() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v %v\" \n\n", "1 deb left in beta, 3 debs in stable", result.From.Debs, result.To.Debs)
+	}
+	pool := filepath.Join(dir, "pool", "main", "c", "com.yourcompany.tweakexample")
+	for _, arch := range []string{"iphoneos-arm", "iphoneos-arm64"} {
+		if _, err := os.Stat(filepath.Join(pool, "com.yourcompany.tweakexample_0.0.1-2_"+arch+".deb")); err != nil {
+			t.Errorf("Promote() failed test. the deb was removed from the pool. (%v)", err)
+		}
+	}
+	for _, suite := range []string{"beta", "stable"} {
+		if _, err := os.Stat(filepath.Join(dir, suite)); !os.IsNotExist(err) {
+			t.Errorf("Promote() failed test. the suite %s has a directory of its own. (%v)", suite, err)
+		}
+	}
+	release, _ := ioutil.ReadFile(filepath.Join(dir, "dists", "stable", "Release"))
+	if !strings.Contains(string(release), "Suite: stable\n") {
+		t.Errorf("Promote() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", "Suite: stable", release)
+	}
+	packages, _ := ioutil.ReadFile(filepath.Join(dir, "Packages"))
+	if strings.Contains(string(packages), "com.yourcompany.tweakexample") {
+		t.Errorf("Promote() failed test. the package is still listed in the Packages file of beta.")
+	}
+
+	var paramTests = []struct {
+		pkg, version, from, to string
+		want                   string
+	}{
+		{"com.yourcompany.tweakexample", "0.0.1-2", "beta", "testing", "*repo.SuiteNotFoundError"},
+		{"com.yourcompany.tweakexample", "0.0.1-2", "beta", "stable", "*repo.PackageNotFoundError"},
+		{"com.yourcompany.tweakexample", "0.0.1-1", "stable", "beta", "*repo.PackageNotFoundError"},
+	}
+	for _, p := range paramTests {
+		_, err := r.Promote(p.pkg, p.version, p.from, p.to)
+		got := "<nil>"
+		switch err.(type) {
+		case *SuiteNotFoundError:
+			got = "*repo.SuiteNotFoundError"
+		case *PackageNotFoundError:
+			got = "*repo.PackageNotFoundError"
+		case nil:
+		default:
+			got = err.Error()
+		}
+		if got != p.want {
+			t.Errorf("Promote(%q, %q, %q, %q) failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" \n\n", p.pkg, p.version, p.from, p.to, p.want, got)
+		}
+	}
+}
+
+// Testing repo operations return typed errors.
+func TestErrors(t *testing.T) {
+	empty, err := ioutil.TempDir("", "afto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(empty)
+	src := tempRepo(t)
+	defer os.RemoveAll(src)
+	dir := filepath.Join(src, "repo")
+	if _, err := New(dir, afutil.DefaultConfig()).Create(src); err != nil {
+		t.Fatal(err)
+	}
+	notDeb := filepath.Join(empty, "broken.deb")
+	ioutil.WriteFile(notDeb, []byte("not a deb"), 0644)
+	signed := afutil.DefaultConfig()
+	signed.SigningKey = filepath.Join(empty, "missing.asc")
+
+	var paramTests = []struct {
+		name string
+		run  func() error
+		want string
+	}{
+		{"create without debs", func() error {
+			_, err := New(filepath.Join(empty, "repo"), afutil.DefaultConfig()).Create(filepath.Join(empty, "nothing"))
+			return err
+		}, "*repo.DebNotFoundError"},
+		{"update with a missing deb", func() error {
+			_, err := New(dir, afutil.DefaultConfig()).Update(filepath.Join(empty, "missing.deb"))
+			return err
+		}, "*repo.DebNotFoundError"},
+		{"update with a broken deb", func() error {
+			_, err := New(dir, afutil.DefaultConfig()).Update(notDeb)
+			return err
+		}, "*repo.ParseError"},
+		{"update with the same version", func() error {
+			_, err := New(dir, afutil.DefaultConfig()).Update(testDeb)
+			return err
+		}, "*repo.UpToDateError"},
+		{"update a directory which is not a repo", func() error {
+			_, err := New(src, afutil.DefaultConfig()).Update(testDeb)
+			return err
+		}, "*repo.InvalidRepoError"},
+		{"regenerate with a missing key", func() error {
+			_, err := New(dir, signed).Regenerate()
+			return err
+		}, "*repo.SignError"},
+		{"sign a directory which is not a repo", func() error {
+			return New(empty, afutil.DefaultConfig()).Sign(testDeb, nil)
+		}, "*repo.InvalidRepoError"},
+		{"regenerate with a directory in place of Packages.gz", func() error {
+			blocked := filepath.Join(dir, "Packages.gz")
+			os.Remove(blocked)
+			if err := os.MkdirAll(filepath.Join(blocked, "blocked"), 0755); err != nil {
+				return err
+			}
+			defer os.RemoveAll(blocked)
+			_, err := New(dir, afutil.DefaultConfig()).Regenerate()
+			return err
+		}, "*repo.IOError"},
+		{"regenerate a signed repo without a key", func() error {
+			key, _ := filepath.Abs("../test_data/keys/secret.asc")
+			if err := New(dir, afutil.DefaultConfig()).Sign(key, []byte("afto")); err != nil {
+				return err
+			}
+			_, err := New(dir, afutil.DefaultConfig()).Regenerate()
+			return err
+		}, "*repo.UnsignedError"},
+	}
+
+	for _, p := range paramTests {
+		err := p.run()
+		got := "<nil>"
+		switch err.(type) {
+		case *DebNotFoundError:
+			got = "*repo.DebNotFoundError"
+		case *ParseError:
+			got = "*repo.ParseError"
+		case *UpToDateError:
+			got = "*repo.UpToDateError"
+		case *SignError:
+			got = "*repo.SignError"
+		case *InvalidRepoError:
+			got = "*repo.InvalidRepoError"
+		case *IOError:
+			got = "*repo.IOError"
+		case *UnsignedError:
+			got = "*repo.UnsignedError"
+		case nil:
+		default:
+			got = err.Error()
+		}
+		if got != p.want {
+			t.Errorf("%s failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%s\" (%v) \n\n", p.name, p.want, got, err)
+		}
+	}
+
+	// Forced, the signed repo is published unsigned and reported so.
+	r := New(dir, afutil.DefaultConfig())
+	r.SetForce(true)
+	result, err := r.Regenerate()
+	if signed, _ := afutil.IsSigned(dir); err != nil || !result.Unsigned || signed {
+		t.Errorf("Regenerate() failed test. \n\n\rWant: \n\r\"%s\" \n\rGot: \n\r\"%v\" (%v) \n\n", "unsigned repo", result, err)
+	}
+}